@@ -2,6 +2,7 @@ package ams
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 
 	"github.com/pkg/errors"
@@ -45,6 +46,40 @@ func (c *Client) CreateAccessPolicy(ctx context.Context, name string, durationIn
 	return &out, nil
 }
 
+func (c *Client) GetAccessPolicy(ctx context.Context, accessPolicyID string) (*AccessPolicy, error) {
+	c.logger.Printf("[INFO] get access policy #%s ...", accessPolicyID)
+
+	endpoint := toAccessPolicyResource(accessPolicyID)
+	var out AccessPolicy
+	if err := c.get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return &out, nil
+}
+
+// GetAccessPolicies returns every access policy in the account, requesting them page by page.
+func (c *Client) GetAccessPolicies(ctx context.Context) ([]AccessPolicy, error) {
+	c.logger.Printf("[INFO] get access policies ...")
+
+	var accessPolicies []AccessPolicy
+	err := c.getList(ctx, accessPoliciesEndpoint, func(value json.RawMessage) (int, error) {
+		var page []AccessPolicy
+		if err := json.Unmarshal(value, &page); err != nil {
+			return 0, err
+		}
+		accessPolicies = append(accessPolicies, page...)
+		return len(page), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return accessPolicies, nil
+}
+
 func (c *Client) DeleteAccessPolicy(ctx context.Context, accessPolicyID string) error {
 	endpoint := toAccessPolicyResource(accessPolicyID)
 	req, err := c.newRequest(ctx, http.MethodDelete, endpoint)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -77,6 +78,66 @@ func TestClient_CreateAccessPolicy(t *testing.T) {
 	}
 }
 
+func TestClient_GetAccessPolicy(t *testing.T) {
+	expected := &AccessPolicy{
+		ID:                "nb:pid:UUID:sample-policy",
+		Created:           formatTime(time.Now()),
+		LastModified:      formatTime(time.Now()),
+		Name:              "ViewPolicy",
+		DurationInMinutes: 300,
+		Permissions:       PermissionRead,
+	}
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/AccessPolicies('%v')", expected.ID),
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, expected),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	actual, err := client.GetAccessPolicy(context.TODO(), expected.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected access policy. expected: %#v, actual: %#v", expected, actual)
+	}
+}
+
+func TestClient_GetAccessPolicies(t *testing.T) {
+	expected := []AccessPolicy{
+		{
+			ID:                "nb:pid:UUID:sample-policy-1",
+			Name:              "UploadPolicy",
+			DurationInMinutes: 440,
+			Permissions:       PermissionWrite,
+		},
+		{
+			ID:                "nb:pid:UUID:sample-policy-2",
+			Name:              "ViewPolicy",
+			DurationInMinutes: 300,
+			Permissions:       PermissionRead,
+		},
+	}
+	m := http.NewServeMux()
+	m.HandleFunc("/AccessPolicies",
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue(expected)),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	actual, err := client.GetAccessPolicies(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected access policies. expected: %#v, actual: %#v", expected, actual)
+	}
+}
+
 func TestClient_DeleteAccessPolicy(t *testing.T) {
 	accessPolicyID := "nb:pid:UUID:sample-policy"
 	m := http.NewServeMux()
@@ -0,0 +1,203 @@
+package amsutil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/recruit-tech/go-ams"
+)
+
+type accessPolicyKey struct {
+	permissions       int
+	durationInMinutes float64
+}
+
+func (k accessPolicyKey) name() string {
+	return fmt.Sprintf("PooledPolicy-%d-%v", k.permissions, k.durationInMinutes)
+}
+
+// AccessPolicyPool shares an access policy between the locators which need the same permissions and duration,
+// since the number of access policies in an account is limited.
+// A pooled access policy is deleted when it is released by everyone in the process and no locator uses it.
+// The ones still used by locators are kept in the pool, and Sweep deletes them once their locators are gone.
+type AccessPolicyPool struct {
+	client *ams.Client
+
+	m        sync.Mutex
+	policies map[accessPolicyKey]*pooledAccessPolicy
+	byID     map[string]*pooledAccessPolicy
+}
+
+// pooledAccessPolicy is guarded by its own lock, so the requests for one key don't block the others.
+// It is locked before AccessPolicyPool.m when both are held.
+type pooledAccessPolicy struct {
+	m      sync.Mutex
+	policy *ams.AccessPolicy
+	refs   int
+}
+
+func NewAccessPolicyPool(client *ams.Client) (*AccessPolicyPool, error) {
+	if client == nil {
+		return nil, errors.New("missing client")
+	}
+	return &AccessPolicyPool{
+		client:   client,
+		policies: make(map[accessPolicyKey]*pooledAccessPolicy),
+		byID:     make(map[string]*pooledAccessPolicy),
+	}, nil
+}
+
+// Acquire finds or creates the shared access policy for (permissions, durationInMinutes).
+// Each call must be paired with Release.
+func (p *AccessPolicyPool) Acquire(ctx context.Context, permissions int, durationInMinutes float64) (*ams.AccessPolicy, error) {
+	if ctx == nil {
+		return nil, errors.New("missing ctx")
+	}
+	if durationInMinutes <= 0 {
+		return nil, errors.New("durationInMinutes must be greater than 0")
+	}
+
+	key := accessPolicyKey{permissions: permissions, durationInMinutes: durationInMinutes}
+	p.m.Lock()
+	entry, ok := p.policies[key]
+	if !ok {
+		entry = &pooledAccessPolicy{}
+		p.policies[key] = entry
+	}
+	p.m.Unlock()
+
+	entry.m.Lock()
+	defer entry.m.Unlock()
+
+	if entry.policy == nil {
+		found, err := p.find(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if found == nil {
+			found, err = p.client.CreateAccessPolicy(ctx, key.name(), durationInMinutes, permissions)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to create access policy")
+			}
+		}
+		entry.policy = found
+
+		p.m.Lock()
+		p.byID[found.ID] = entry
+		p.m.Unlock()
+	}
+	entry.refs++
+	return entry.policy, nil
+}
+
+func (p *AccessPolicyPool) find(ctx context.Context, key accessPolicyKey) (*ams.AccessPolicy, error) {
+	accessPolicies, err := p.client.GetAccessPolicies(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get access policies")
+	}
+	for _, accessPolicy := range accessPolicies {
+		if accessPolicy.Name == key.name() && accessPolicy.Permissions == key.permissions && accessPolicy.DurationInMinutes == key.durationInMinutes {
+			return &accessPolicy, nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *AccessPolicyPool) lookup(accessPolicyID string) *pooledAccessPolicy {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return p.byID[accessPolicyID]
+}
+
+// Release drops a reference acquired by Acquire.
+// When the last reference is dropped, the access policy is deleted unless a locator still uses it.
+func (p *AccessPolicyPool) Release(ctx context.Context, accessPolicyID string) error {
+	if ctx == nil {
+		return errors.New("missing ctx")
+	}
+
+	entry := p.lookup(accessPolicyID)
+	if entry == nil {
+		return errors.Errorf("access policy not acquired. accessPolicyID='%v'", accessPolicyID)
+	}
+
+	entry.m.Lock()
+	defer entry.m.Unlock()
+
+	if entry.refs == 0 || entry.policy == nil || entry.policy.ID != accessPolicyID {
+		return errors.Errorf("access policy not acquired. accessPolicyID='%v'", accessPolicyID)
+	}
+	entry.refs--
+	if entry.refs > 0 {
+		return nil
+	}
+	return p.deleteUnused(ctx, entry)
+}
+
+// Sweep deletes the pooled access policies which are not acquired and no longer used by any locator,
+// e.g. after the locators which kept them on Release have expired or been deleted.
+func (p *AccessPolicyPool) Sweep(ctx context.Context) error {
+	if ctx == nil {
+		return errors.New("missing ctx")
+	}
+
+	p.m.Lock()
+	entries := make([]*pooledAccessPolicy, 0, len(p.policies))
+	for _, entry := range p.policies {
+		entries = append(entries, entry)
+	}
+	p.m.Unlock()
+
+	for _, entry := range entries {
+		entry.m.Lock()
+		var err error
+		if entry.refs == 0 && entry.policy != nil {
+			err = p.deleteUnused(ctx, entry)
+		}
+		entry.m.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteUnused deletes the access policy of entry unless a locator uses it. The caller must hold entry.m.
+func (p *AccessPolicyPool) deleteUnused(ctx context.Context, entry *pooledAccessPolicy) error {
+	locators, err := p.client.GetLocatorsWithAccessPolicy(ctx, entry.policy.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get locators")
+	}
+	if len(locators) != 0 {
+		return nil
+	}
+
+	if err := p.client.DeleteAccessPolicy(ctx, entry.policy.ID); err != nil {
+		return errors.Wrap(err, "failed to delete access policy")
+	}
+
+	p.m.Lock()
+	delete(p.byID, entry.policy.ID)
+	p.m.Unlock()
+	entry.policy = nil
+	return nil
+}
+
+func acquireAccessPolicy(ctx context.Context, client *ams.Client, pool *AccessPolicyPool, name string, durationInMinutes float64, permissions int) (*ams.AccessPolicy, func(), error) {
+	if pool != nil {
+		accessPolicy, err := pool.Acquire(ctx, permissions, durationInMinutes)
+		if err != nil {
+			return nil, nil, err
+		}
+		return accessPolicy, func() { pool.Release(ctx, accessPolicy.ID) }, nil
+	}
+
+	accessPolicy, err := client.CreateAccessPolicy(ctx, name, durationInMinutes, permissions)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create access policy")
+	}
+	return accessPolicy, func() { client.DeleteAccessPolicy(ctx, accessPolicy.ID) }, nil
+}
@@ -0,0 +1,134 @@
+package amsutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/recruit-tech/go-ams"
+)
+
+func TestAccessPolicyPool(t *testing.T) {
+	var (
+		created  int
+		deleted  []string
+		locators []ams.Locator
+	)
+	existing := ams.AccessPolicy{ID: "existing-policy-id", Name: "PooledPolicy-1-60", DurationInMinutes: 60, Permissions: ams.PermissionRead}
+
+	m := http.NewServeMux()
+	m.HandleFunc("/AccessPolicies", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{"value": []ams.AccessPolicy{existing}})
+		case http.MethodPost:
+			var accessPolicy ams.AccessPolicy
+			if err := json.NewDecoder(r.Body).Decode(&accessPolicy); err != nil {
+				t.Fatal(err)
+			}
+			created++
+			accessPolicy.ID = "created-policy-id"
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(accessPolicy)
+		}
+	})
+	for _, id := range []string{"existing-policy-id", "created-policy-id"} {
+		id := id
+		m.HandleFunc(fmt.Sprintf("/AccessPolicies('%v')", id), func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodDelete {
+				t.Errorf("unexpected method: %v", r.Method)
+			}
+			deleted = append(deleted, id)
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+	m.HandleFunc("/Locators", func(w http.ResponseWriter, r *http.Request) {
+		var value []ams.Locator
+		for _, locator := range locators {
+			if r.URL.Query().Get("$filter") == fmt.Sprintf("AccessPolicyId eq '%v'", locator.AccessPolicyID) {
+				value = append(value, locator)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": value})
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := NewAccessPolicyPool(client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.TODO()
+
+	t.Run("reuseExisting", func(t *testing.T) {
+		ap, err := pool.Acquire(ctx, ams.PermissionRead, 60)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ap.ID != existing.ID {
+			t.Errorf("unexpected access policy. expected: %v, actual: %v", existing.ID, ap.ID)
+		}
+		locators = []ams.Locator{{ID: "published-locator-id", AccessPolicyID: ap.ID}}
+		if err := pool.Release(ctx, ap.ID); err != nil {
+			t.Fatal(err)
+		}
+		if len(deleted) != 0 {
+			t.Errorf("delete access policy in use: %v", deleted)
+		}
+	})
+
+	t.Run("createAndShare", func(t *testing.T) {
+		ap1, err := pool.Acquire(ctx, ams.PermissionWrite, 440)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ap2, err := pool.Acquire(ctx, ams.PermissionWrite, 440)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created != 1 || ap1.ID != ap2.ID {
+			t.Errorf("access policy not shared. created: %v, ids: %v, %v", created, ap1.ID, ap2.ID)
+		}
+
+		if err := pool.Release(ctx, ap1.ID); err != nil {
+			t.Fatal(err)
+		}
+		if len(deleted) != 0 {
+			t.Errorf("delete referenced access policy: %v", deleted)
+		}
+		if err := pool.Release(ctx, ap2.ID); err != nil {
+			t.Fatal(err)
+		}
+		if len(deleted) != 1 || deleted[0] != ap1.ID {
+			t.Errorf("unexpected deleted access policies: %v", deleted)
+		}
+		if err := pool.Release(ctx, ap2.ID); err == nil {
+			t.Error("accept release without acquire")
+		}
+	})
+
+	t.Run("sweep", func(t *testing.T) {
+		deleted = nil
+		if err := pool.Sweep(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if len(deleted) != 0 {
+			t.Errorf("delete access policy in use: %v", deleted)
+		}
+
+		locators = nil
+		if err := pool.Sweep(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if len(deleted) != 1 || deleted[0] != existing.ID {
+			t.Errorf("unexpected deleted access policies: %v", deleted)
+		}
+	})
+}
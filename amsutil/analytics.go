@@ -0,0 +1,142 @@
+package amsutil
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/recruit-tech/go-ams"
+)
+
+// Analyze submits a job which runs the Media Analytics processor of config on the asset.
+// The processor is resolved by name, picking its latest version.
+func Analyze(ctx context.Context, client *ams.Client, assetID string, config ams.AnalyticsConfiguration, opts ...ams.JobOption) (*ams.Job, error) {
+	if ctx == nil {
+		return nil, errors.New("missing ctx")
+	}
+	if client == nil {
+		return nil, errors.New("missing client")
+	}
+	if len(assetID) == 0 {
+		return nil, errors.New("missing assetID")
+	}
+	if config == nil {
+		return nil, errors.New("missing config")
+	}
+
+	configuration, err := config.Configuration()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid configuration")
+	}
+	mediaProcessor, err := client.ResolveMediaProcessor(ctx, config.MediaProcessorName(), "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve media processor. name='%v'", config.MediaProcessorName())
+	}
+	job, err := client.AddEncodeJobWithConfiguration(ctx, assetID, mediaProcessor.ID, configuration, "", opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to analyze asset. assetID='%v'", assetID)
+	}
+	return job, nil
+}
+
+// The Media Analytics processors name their outputs after the input file with these suffixes,
+// next to the thumbnails and other files which some of them write into the same asset.
+const (
+	faceDetectionOutputSuffix     = "_annotations.json"
+	motionDetectionOutputSuffix   = "_motion.json"
+	ocrOutputSuffix               = "_ocr.json"
+	contentModerationOutputSuffix = "_modoutput.json"
+	indexerCaptionsOutputSuffix   = ".vtt"
+)
+
+func GetFaceDetectionResult(ctx context.Context, client *ams.Client, outputAssetID string) (*ams.FaceDetectionResult, error) {
+	var result *ams.FaceDetectionResult
+	err := decodeAnalyticsOutput(ctx, client, outputAssetID, faceDetectionOutputSuffix, func(r io.Reader) (err error) {
+		result, err = ams.ParseFaceDetectionResult(r)
+		return err
+	})
+	return result, err
+}
+
+func GetMotionDetectionResult(ctx context.Context, client *ams.Client, outputAssetID string) (*ams.MotionDetectionResult, error) {
+	var result *ams.MotionDetectionResult
+	err := decodeAnalyticsOutput(ctx, client, outputAssetID, motionDetectionOutputSuffix, func(r io.Reader) (err error) {
+		result, err = ams.ParseMotionDetectionResult(r)
+		return err
+	})
+	return result, err
+}
+
+func GetOCRResult(ctx context.Context, client *ams.Client, outputAssetID string) (*ams.OCRResult, error) {
+	var result *ams.OCRResult
+	err := decodeAnalyticsOutput(ctx, client, outputAssetID, ocrOutputSuffix, func(r io.Reader) (err error) {
+		result, err = ams.ParseOCRResult(r)
+		return err
+	})
+	return result, err
+}
+
+func GetContentModerationResult(ctx context.Context, client *ams.Client, outputAssetID string) (*ams.ContentModerationResult, error) {
+	var result *ams.ContentModerationResult
+	err := decodeAnalyticsOutput(ctx, client, outputAssetID, contentModerationOutputSuffix, func(r io.Reader) (err error) {
+		result, err = ams.ParseContentModerationResult(r)
+		return err
+	})
+	return result, err
+}
+
+// GetIndexerCaptions decodes the WebVTT captions generated by Azure Media Indexer with IndexerCaptionFormatWebVTT.
+func GetIndexerCaptions(ctx context.Context, client *ams.Client, outputAssetID string) ([]ams.WebVTTCue, error) {
+	var cues []ams.WebVTTCue
+	err := decodeAnalyticsOutput(ctx, client, outputAssetID, indexerCaptionsOutputSuffix, func(r io.Reader) (err error) {
+		cues, err = ams.ParseWebVTT(r)
+		return err
+	})
+	return cues, err
+}
+
+// decodeAnalyticsOutput decodes the output file of the asset whose name ends with suffix.
+// It fails if the asset has no such file or more than one.
+func decodeAnalyticsOutput(ctx context.Context, client *ams.Client, outputAssetID, suffix string, decode func(io.Reader) error) error {
+	if ctx == nil {
+		return errors.New("missing ctx")
+	}
+	if client == nil {
+		return errors.New("missing client")
+	}
+
+	assetFiles, err := client.GetAssetFiles(ctx, outputAssetID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get asset files. assetID='%v'", outputAssetID)
+	}
+	var name string
+	for _, assetFile := range assetFiles {
+		if !strings.HasSuffix(strings.ToLower(assetFile.Name), suffix) {
+			continue
+		}
+		if len(name) != 0 {
+			return errors.Errorf("multiple analytics results (*%v) found. assetID='%v'", suffix, outputAssetID)
+		}
+		name = assetFile.Name
+	}
+	if len(name) == 0 {
+		return errors.Errorf("analytics result (*%v) not found. assetID='%v'", suffix, outputAssetID)
+	}
+
+	found := false
+	match := func(assetFile ams.AssetFile) bool {
+		return assetFile.Name == name
+	}
+	err = DownloadAssetFiles(ctx, client, outputAssetID, match, func(assetFile ams.AssetFile, r io.Reader) error {
+		found = true
+		return decode(r)
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.Errorf("analytics result not found. assetID='%v', name='%v'", outputAssetID, name)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package amsutil
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/recruit-tech/go-ams"
+)
+
+const (
+	downloadPolicyName       = "DownloadPolicy"
+	downloadDurationInMinute = 60.0
+)
+
+// DownloadAssetFiles calls fn with the content of each asset file which match reports true.
+// A temporary SAS locator is created for reading and deleted when all files are processed.
+func DownloadAssetFiles(ctx context.Context, client *ams.Client, assetID string, match func(ams.AssetFile) bool, fn func(ams.AssetFile, io.Reader) error) error {
+	if ctx == nil {
+		return errors.New("missing ctx")
+	}
+	if client == nil {
+		return errors.New("missing client")
+	}
+	if len(assetID) == 0 {
+		return errors.New("missing assetID")
+	}
+	if match == nil {
+		return errors.New("missing match")
+	}
+	if fn == nil {
+		return errors.New("missing fn")
+	}
+
+	assetFiles, err := client.GetAssetFiles(ctx, assetID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get asset files. assetID='%v'", assetID)
+	}
+
+	var targets []ams.AssetFile
+	for _, assetFile := range assetFiles {
+		if match(assetFile) {
+			targets = append(targets, assetFile)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	accessPolicy, err := client.CreateAccessPolicy(ctx, downloadPolicyName, downloadDurationInMinute, ams.PermissionRead)
+	if err != nil {
+		return errors.Wrap(err, "failed to create access policy")
+	}
+	defer client.DeleteAccessPolicy(ctx, accessPolicy.ID)
+
+	// for clock skew
+	startTime := TimeNow().Add(-5 * time.Minute)
+	locator, err := client.CreateLocator(ctx, accessPolicy.ID, assetID, startTime, ams.LocatorSAS)
+	if err != nil {
+		return errors.Wrap(err, "failed to create locator")
+	}
+	defer client.DeleteLocator(ctx, locator.ID)
+
+	for _, assetFile := range targets {
+		if err := downloadAssetFile(ctx, client, locator, assetFile, fn); err != nil {
+			return errors.Wrapf(err, "failed to download asset file. name='%v'", assetFile.Name)
+		}
+	}
+	return nil
+}
+
+func downloadAssetFile(ctx context.Context, client *ams.Client, locator *ams.Locator, assetFile ams.AssetFile, fn func(ams.AssetFile, io.Reader) error) error {
+	downloadURL, err := locator.ToUploadURL(assetFile.Name)
+	if err != nil {
+		return errors.Wrap(err, "failed to construct download url")
+	}
+	blob, err := client.GetBlob(ctx, downloadURL)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+
+	return fn(assetFile, blob)
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/recruit-tech/go-ams"
 )
 
-func Encode(ctx context.Context, client *ams.Client, assetID, mediaProcessorID, configuration string) ([]ams.Asset, *ams.Job, error) {
+// Encode encodes the asset with configuration, which is either a system preset name (ams.Preset*) or
+// a custom preset built by ams.PresetBuilder. opts set the job/task names, priority and output asset options.
+func Encode(ctx context.Context, client *ams.Client, assetID, mediaProcessorID, configuration string, opts ...ams.JobOption) ([]ams.Asset, *ams.Job, error) {
 	if ctx == nil {
 		return nil, nil, errors.New("missing ctx")
 	}
@@ -29,7 +31,7 @@ func Encode(ctx context.Context, client *ams.Client, assetID, mediaProcessorID,
 		return nil, nil, errors.Wrapf(err, "failed to get asset. assetID='%v'", assetID)
 	}
 
-	job, err := client.AddEncodeJob(ctx, asset.ID, mediaProcessorID, "")
+	job, err := client.AddEncodeJobWithConfiguration(ctx, asset.ID, mediaProcessorID, configuration, "", opts...)
 	if err != nil {
 		return nil, nil, errors.Wrapf(err, "failed to encode asset. assetID='%v'", asset.ID)
 	}
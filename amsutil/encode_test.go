@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/recruit-tech/go-ams"
 )
 
 func TestEncode(t *testing.T) {
@@ -27,24 +29,12 @@ func TestEncode(t *testing.T) {
 		t.Fatalf("file uploading failed: %v", err)
 	}
 
-	mediaProcessors, err := AMS.GetMediaProcessors(ctx)
+	MES, err := AMS.ResolveMediaProcessor(ctx, ams.MediaProcessorMediaEncoderStandard, "")
 	if err != nil {
-		t.Fatalf("get media processors failed: %v", err)
-	}
-
-	var MES string
-	for _, mediaProcessor := range mediaProcessors {
-		if mediaProcessor.Name == "Media Encoder Standard" {
-			MES = mediaProcessor.ID
-			break
-		}
-	}
-
-	if len(MES) == 0 {
-		t.Fatal("'Media Encoder Standard' not found")
+		t.Fatalf("resolve media processor failed: %v", err)
 	}
 
-	encodedAssets, job, err := Encode(ctx, AMS, asset.ID, MES, "Adaptive Streaming")
+	encodedAssets, job, err := Encode(ctx, AMS, asset.ID, MES.ID, ams.PresetAdaptiveStreaming)
 	if err != nil {
 		t.Fatalf("encode rejected: %v", err)
 	}
@@ -53,6 +43,16 @@ func TestEncode(t *testing.T) {
 		t.Fatalf("encode failed: %v", err)
 	}
 
+	for _, encodedAsset := range encodedAssets {
+		metadata, err := GetAssetMetadata(ctx, AMS, encodedAsset.ID)
+		if err != nil {
+			t.Errorf("get metadata failed [asset#%v]: %v", encodedAsset.ID, err)
+		}
+		if metadata == nil || len(metadata.Outputs) == 0 {
+			t.Errorf("metadata not found [asset#%v]", encodedAsset.ID)
+		}
+	}
+
 	if err := AMS.DeleteAsset(ctx, asset.ID); err != nil {
 		t.Fatalf("delete failed: %v", err)
 	}
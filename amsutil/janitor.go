@@ -0,0 +1,137 @@
+package amsutil
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/recruit-tech/go-ams"
+)
+
+// JanitorReport lists the resources which RunJanitor deleted, or would delete in dry-run mode.
+type JanitorReport struct {
+	ExpiredLocators      []ams.Locator
+	OrphanedLocators     []ams.Locator
+	UnusedAccessPolicies []ams.AccessPolicy
+
+	// Errors holds the failures of deletion.
+	Errors []error
+}
+
+// RunJanitor finds locators which have expired, locators whose asset is gone and access policies no locator references,
+// and deletes them with at most workers requests in parallel unless dryRun is true.
+// Access policies modified within gracePeriod are kept, since a locator may be about to reference them.
+func RunJanitor(ctx context.Context, client *ams.Client, gracePeriod time.Duration, dryRun bool, workers uint) (*JanitorReport, error) {
+	if ctx == nil {
+		return nil, errors.New("missing ctx")
+	}
+	if client == nil {
+		return nil, errors.New("missing client")
+	}
+	if workers == 0 {
+		return nil, errors.New("workers must be greater than 0")
+	}
+
+	// each list is requested after the ones which may reference it,
+	// so a resource created during the scan doesn't look orphaned or unused.
+	accessPolicies, err := client.GetAccessPolicies(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get access policies")
+	}
+	locators, err := client.GetLocators(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get locators")
+	}
+	assets, err := client.GetAssets(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get assets")
+	}
+
+	assetIDs := make(map[string]bool)
+	for _, asset := range assets {
+		assetIDs[asset.ID] = true
+	}
+
+	now := TimeNow()
+	report := &JanitorReport{}
+	references := make(map[string]bool)
+	for _, locator := range locators {
+		if !assetIDs[locator.AssetID] {
+			report.OrphanedLocators = append(report.OrphanedLocators, locator)
+			continue
+		}
+		if expiration, err := parseTime(locator.ExpirationDateTime); err == nil && expiration.Before(now) {
+			report.ExpiredLocators = append(report.ExpiredLocators, locator)
+			continue
+		}
+		references[locator.AccessPolicyID] = true
+	}
+	for _, accessPolicy := range accessPolicies {
+		if references[accessPolicy.ID] {
+			continue
+		}
+		if modified, err := parseTime(accessPolicy.LastModified); err != nil || now.Sub(modified) < gracePeriod {
+			continue
+		}
+		report.UnusedAccessPolicies = append(report.UnusedAccessPolicies, accessPolicy)
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	var deletions []func() error
+	for _, locators := range [][]ams.Locator{report.ExpiredLocators, report.OrphanedLocators} {
+		for _, locator := range locators {
+			locatorID := locator.ID
+			deletions = append(deletions, func() error {
+				return errors.Wrapf(client.DeleteLocator(ctx, locatorID), "failed to delete locator. locatorID='%v'", locatorID)
+			})
+		}
+	}
+	report.Errors = runParallel(deletions, workers)
+
+	// access policies can't be deleted while a locator references them
+	deletions = nil
+	for _, accessPolicy := range report.UnusedAccessPolicies {
+		accessPolicyID := accessPolicy.ID
+		deletions = append(deletions, func() error {
+			return errors.Wrapf(client.DeleteAccessPolicy(ctx, accessPolicyID), "failed to delete access policy. accessPolicyID='%v'", accessPolicyID)
+		})
+	}
+	report.Errors = append(report.Errors, runParallel(deletions, workers)...)
+
+	if len(report.Errors) != 0 {
+		return report, errors.Errorf("failed to clean up: (%d error occurred)", len(report.Errors))
+	}
+	return report, nil
+}
+
+func runParallel(fns []func() error, workers uint) []error {
+	var (
+		m    sync.Mutex
+		errs []error
+	)
+	sem := make(chan struct{}, workers)
+	wg := new(sync.WaitGroup)
+	for _, fn := range fns {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(fn func() error) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(); err != nil {
+				m.Lock()
+				errs = append(errs, err)
+				m.Unlock()
+			}
+		}(fn)
+	}
+	wg.Wait()
+	return errs
+}
+
+func parseTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,163 @@
+package amsutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recruit-tech/go-ams"
+)
+
+func TestRunJanitor(t *testing.T) {
+	now := time.Date(2017, 8, 10, 0, 0, 0, 0, time.UTC)
+	TimeNow = func() time.Time { return now }
+	defer func() { TimeNow = time.Now }()
+
+	past := now.Add(-48 * time.Hour).Format(time.RFC3339)
+	future := now.Add(48 * time.Hour).Format(time.RFC3339)
+
+	assets := []ams.Asset{{ID: "asset"}}
+	locators := []ams.Locator{
+		{ID: "active", AssetID: "asset", AccessPolicyID: "policy-active", ExpirationDateTime: future},
+		{ID: "expired", AssetID: "asset", AccessPolicyID: "policy-expired", ExpirationDateTime: past},
+		{ID: "orphaned", AssetID: "deleted-asset", AccessPolicyID: "policy-orphaned", ExpirationDateTime: future},
+	}
+	accessPolicies := []ams.AccessPolicy{
+		{ID: "policy-active", LastModified: past},
+		{ID: "policy-expired", LastModified: past},
+		{ID: "policy-orphaned", LastModified: past},
+		{ID: "policy-unused", LastModified: past},
+		{ID: "policy-new", LastModified: now.Add(-time.Minute).Format(time.RFC3339)},
+	}
+
+	var (
+		m       sync.Mutex
+		deleted []string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Assets", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": assets})
+	})
+	mux.HandleFunc("/Locators", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": locators})
+	})
+	mux.HandleFunc("/AccessPolicies", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": accessPolicies})
+	})
+	handleDelete := func(resource string) {
+		mux.HandleFunc("/"+resource, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodDelete {
+				t.Errorf("unexpected method: %v", r.Method)
+			}
+			m.Lock()
+			deleted = append(deleted, resource)
+			m.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+	for _, locator := range locators {
+		handleDelete(fmt.Sprintf("Locators('%v')", locator.ID))
+	}
+	for _, accessPolicy := range accessPolicies {
+		handleDelete(fmt.Sprintf("AccessPolicies('%v')", accessPolicy.ID))
+	}
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("dryRun", func(t *testing.T) {
+		report, err := RunJanitor(context.TODO(), client, time.Hour, true, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.ExpiredLocators) != 1 || report.ExpiredLocators[0].ID != "expired" {
+			t.Errorf("unexpected expired locators: %#v", report.ExpiredLocators)
+		}
+		if len(report.OrphanedLocators) != 1 || report.OrphanedLocators[0].ID != "orphaned" {
+			t.Errorf("unexpected orphaned locators: %#v", report.OrphanedLocators)
+		}
+		var unused []string
+		for _, accessPolicy := range report.UnusedAccessPolicies {
+			unused = append(unused, accessPolicy.ID)
+		}
+		if expected := []string{"policy-expired", "policy-orphaned", "policy-unused"}; !reflect.DeepEqual(unused, expected) {
+			t.Errorf("unexpected unused access policies. expected: %v, actual: %v", expected, unused)
+		}
+		if len(deleted) != 0 {
+			t.Errorf("delete resources in dry-run: %v", deleted)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if _, err := RunJanitor(context.TODO(), client, time.Hour, false, 2); err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(deleted)
+		expected := []string{
+			"AccessPolicies('policy-expired')",
+			"AccessPolicies('policy-orphaned')",
+			"AccessPolicies('policy-unused')",
+			"Locators('expired')",
+			"Locators('orphaned')",
+		}
+		if !reflect.DeepEqual(deleted, expected) {
+			t.Errorf("unexpected deleted resources. expected: %v, actual: %v", expected, deleted)
+		}
+	})
+}
+
+func TestRunJanitor_paged(t *testing.T) {
+	// AMS returns at most 1000 entities per request
+	const pageSize = 1000
+	var assets []ams.Asset
+	for i := 0; i < pageSize+1; i++ {
+		assets = append(assets, ams.Asset{ID: fmt.Sprintf("asset-%d", i)})
+	}
+	locators := []ams.Locator{{ID: "last-page", AssetID: assets[pageSize].ID, AccessPolicyID: "policy"}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Assets", func(w http.ResponseWriter, r *http.Request) {
+		skip, err := strconv.Atoi(r.URL.Query().Get("$skip"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		end := skip + pageSize
+		if end > len(assets) {
+			end = len(assets)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": assets[skip:end]})
+	})
+	mux.HandleFunc("/Locators", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": locators})
+	})
+	mux.HandleFunc("/AccessPolicies", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": []ams.AccessPolicy{{ID: "policy"}}})
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := RunJanitor(context.TODO(), client, time.Hour, true, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.OrphanedLocators) != 0 {
+		t.Errorf("live locators reported as orphaned: %#v", report.OrphanedLocators)
+	}
+}
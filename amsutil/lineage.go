@@ -0,0 +1,215 @@
+package amsutil
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/recruit-tech/go-ams"
+)
+
+// LineageEdge means that OutputAssetID was derived from InputAssetID.
+// JobID is empty when the job which produced OutputAssetID no longer exists.
+type LineageEdge struct {
+	JobID         string
+	InputAssetID  string
+	OutputAssetID string
+}
+
+type Lineage struct {
+	AssetID string
+	Assets  map[string]ams.Asset
+	Jobs    map[string]ams.Job
+	Edges   []LineageEdge
+}
+
+// Ancestors returns the assets from which the asset was derived, nearest first.
+func (l *Lineage) Ancestors() []ams.Asset {
+	return l.walk(func(e LineageEdge) (string, string) { return e.OutputAssetID, e.InputAssetID })
+}
+
+// Descendants returns the assets derived from the asset, nearest first.
+func (l *Lineage) Descendants() []ams.Asset {
+	return l.walk(func(e LineageEdge) (string, string) { return e.InputAssetID, e.OutputAssetID })
+}
+
+// ProducedBy returns the jobs which output assetID.
+func (l *Lineage) ProducedBy(assetID string) []ams.Job {
+	var jobs []ams.Job
+	seen := make(map[string]bool)
+	for _, e := range l.Edges {
+		if e.OutputAssetID != assetID || len(e.JobID) == 0 || seen[e.JobID] {
+			continue
+		}
+		seen[e.JobID] = true
+		jobs = append(jobs, l.Jobs[e.JobID])
+	}
+	return jobs
+}
+
+func (l *Lineage) walk(direction func(LineageEdge) (from, to string)) []ams.Asset {
+	var assets []ams.Asset
+	visited := map[string]bool{l.AssetID: true}
+	queue := []string{l.AssetID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, e := range l.Edges {
+			from, to := direction(e)
+			if from != current || visited[to] {
+				continue
+			}
+			visited[to] = true
+			queue = append(queue, to)
+			assets = append(assets, l.Assets[to])
+		}
+	}
+	return assets
+}
+
+// GetAssetLineage walks the lineage graph of the asset in both directions.
+// Ancestors are resolved from the ParentAssets of each asset, which remain after their jobs were deleted.
+// Jobs and descendants are resolved from the input and output assets of the jobs created since the earliest ancestor,
+// because a job is never older than its inputs.
+// It costs one request per ancestor, one list request per 1000 of those jobs and two requests per job,
+// so it is expensive for an old asset in a busy account.
+func GetAssetLineage(ctx context.Context, client *ams.Client, assetID string) (*Lineage, error) {
+	if ctx == nil {
+		return nil, errors.New("missing ctx")
+	}
+	if client == nil {
+		return nil, errors.New("missing client")
+	}
+	if len(assetID) == 0 {
+		return nil, errors.New("missing assetID")
+	}
+
+	asset, err := client.GetAsset(ctx, assetID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get asset. assetID='%v'", assetID)
+	}
+	assets := map[string]ams.Asset{asset.ID: *asset}
+
+	// ancestors by ParentAssets
+	parentsByAsset := make(map[string][]ams.Asset)
+	earliest, earliestErr := parseTime(asset.Created)
+	queue := []string{asset.ID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		parents, err := client.GetParentAssets(ctx, current)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get parent assets. assetID='%v'", current)
+		}
+		parentsByAsset[current] = parents
+		for _, parent := range parents {
+			if _, ok := assets[parent.ID]; ok {
+				continue
+			}
+			assets[parent.ID] = parent
+			queue = append(queue, parent.ID)
+			if created, err := parseTime(parent.Created); err == nil && earliestErr == nil && created.Before(earliest) {
+				earliest = created
+			} else if err != nil {
+				earliestErr = err
+			}
+		}
+	}
+
+	var jobsOpts []ams.JobsOption
+	if earliestErr == nil {
+		jobsOpts = append(jobsOpts, ams.SetJobCreatedAfter(earliest))
+	}
+	jobs, err := client.GetJobs(ctx, jobsOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get jobs")
+	}
+
+	var jobEdges []LineageEdge
+	for _, job := range jobs {
+		inputs, err := client.GetInputMediaAssets(ctx, job.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get input media assets. jobID='%v'", job.ID)
+		}
+		outputs, err := client.GetOutputMediaAssets(ctx, job.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get output media assets. jobID='%v'", job.ID)
+		}
+		for _, input := range inputs {
+			assets[input.ID] = input
+			for _, output := range outputs {
+				assets[output.ID] = output
+				jobEdges = append(jobEdges, LineageEdge{JobID: job.ID, InputAssetID: input.ID, OutputAssetID: output.ID})
+			}
+		}
+	}
+
+	lineage := &Lineage{
+		AssetID: asset.ID,
+		Assets:  make(map[string]ams.Asset),
+		Jobs:    make(map[string]ams.Job),
+	}
+	jobsByID := make(map[string]ams.Job)
+	for _, job := range jobs {
+		jobsByID[job.ID] = job
+	}
+	addEdge := func(e LineageEdge) {
+		for _, known := range lineage.Edges {
+			if known.InputAssetID == e.InputAssetID && known.OutputAssetID == e.OutputAssetID && (known.JobID == e.JobID || len(e.JobID) == 0) {
+				return
+			}
+		}
+		lineage.Edges = append(lineage.Edges, e)
+		lineage.Assets[e.InputAssetID] = assets[e.InputAssetID]
+		lineage.Assets[e.OutputAssetID] = assets[e.OutputAssetID]
+		if len(e.JobID) != 0 {
+			lineage.Jobs[e.JobID] = jobsByID[e.JobID]
+		}
+	}
+	lineage.Assets[asset.ID] = *asset
+
+	visited := map[string]bool{asset.ID: true}
+	queue = []string{asset.ID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, e := range jobEdges {
+			if e.OutputAssetID != current {
+				continue
+			}
+			addEdge(e)
+			if !visited[e.InputAssetID] {
+				visited[e.InputAssetID] = true
+				queue = append(queue, e.InputAssetID)
+			}
+		}
+		for _, parent := range parentsByAsset[current] {
+			addEdge(LineageEdge{InputAssetID: parent.ID, OutputAssetID: current})
+			if !visited[parent.ID] {
+				visited[parent.ID] = true
+				queue = append(queue, parent.ID)
+			}
+		}
+	}
+
+	visited = map[string]bool{asset.ID: true}
+	queue = []string{asset.ID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, e := range jobEdges {
+			if e.InputAssetID != current {
+				continue
+			}
+			addEdge(e)
+			if !visited[e.OutputAssetID] {
+				visited[e.OutputAssetID] = true
+				queue = append(queue, e.OutputAssetID)
+			}
+		}
+	}
+
+	return lineage, nil
+}
@@ -0,0 +1,93 @@
+package amsutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/recruit-tech/go-ams"
+)
+
+func TestGetAssetLineage(t *testing.T) {
+	// source --job1--> encoded --job2--> thumbnail
+	// source's parent job was deleted, it only remains as ParentAssets.
+	assets := map[string]ams.Asset{
+		"original":  {ID: "original", Name: "Original", Created: "2018-01-01T00:00:00Z"},
+		"source":    {ID: "source", Name: "Source", Created: "2018-01-02T00:00:00Z"},
+		"encoded":   {ID: "encoded", Name: "Encoded", Created: "2018-01-03T00:00:00Z"},
+		"thumbnail": {ID: "thumbnail", Name: "Thumbnail", Created: "2018-01-04T00:00:00Z"},
+		"unrelated": {ID: "unrelated", Name: "Unrelated", Created: "2018-01-04T00:00:00Z"},
+	}
+	jobs := []ams.Job{
+		{ID: "job1", Name: "Encode"},
+		{ID: "job2", Name: "Thumbnail"},
+		{ID: "job3", Name: "Unrelated"},
+	}
+	inputs := map[string][]string{"job1": {"source"}, "job2": {"encoded"}, "job3": {"unrelated"}}
+	outputs := map[string][]string{"job1": {"encoded"}, "job2": {"thumbnail"}, "job3": {"unrelated"}}
+	parents := map[string][]string{"source": {"original"}, "encoded": {"source"}, "thumbnail": {"encoded"}}
+
+	writeAssets := func(w http.ResponseWriter, ids []string) {
+		var value []ams.Asset
+		for _, id := range ids {
+			value = append(value, assets[id])
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": value})
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc("/Jobs", func(w http.ResponseWriter, r *http.Request) {
+		// jobs older than the earliest ancestor can't be in the lineage
+		if expected, actual := "Created ge datetime'2018-01-01T00:00:00Z'", r.URL.Query().Get("$filter"); actual != expected {
+			t.Errorf("unexpected $filter. expected: %v, actual: %v", expected, actual)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": jobs})
+	})
+	for _, job := range jobs {
+		job := job
+		m.HandleFunc(fmt.Sprintf("/Jobs('%v')/InputMediaAssets", job.ID), func(w http.ResponseWriter, r *http.Request) {
+			writeAssets(w, inputs[job.ID])
+		})
+		m.HandleFunc(fmt.Sprintf("/Jobs('%v')/OutputMediaAssets", job.ID), func(w http.ResponseWriter, r *http.Request) {
+			writeAssets(w, outputs[job.ID])
+		})
+	}
+	for id := range assets {
+		id := id
+		m.HandleFunc(fmt.Sprintf("/Assets('%v')", id), func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(assets[id])
+		})
+		m.HandleFunc(fmt.Sprintf("/Assets('%v')/ParentAssets", id), func(w http.ResponseWriter, r *http.Request) {
+			writeAssets(w, parents[id])
+		})
+	}
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lineage, err := GetAssetLineage(context.TODO(), client, "encoded")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, actual := []ams.Asset{assets["source"], assets["original"]}, lineage.Ancestors(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected ancestors. expected: %#v, actual: %#v", expected, actual)
+	}
+	if expected, actual := []ams.Asset{assets["thumbnail"]}, lineage.Descendants(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected descendants. expected: %#v, actual: %#v", expected, actual)
+	}
+	if expected, actual := []ams.Job{jobs[0]}, lineage.ProducedBy("encoded"); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected jobs. expected: %#v, actual: %#v", expected, actual)
+	}
+	if _, ok := lineage.Assets["unrelated"]; ok {
+		t.Error("lineage contains unrelated asset")
+	}
+}
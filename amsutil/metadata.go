@@ -0,0 +1,52 @@
+package amsutil
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/recruit-tech/go-ams"
+)
+
+const (
+	inputMetadataSuffix  = "_metadata.xml"
+	outputMetadataSuffix = "_manifest.xml"
+)
+
+// EncodedAssetMetadata is the metadata which Media Encoder Standard writes into its output asset.
+type EncodedAssetMetadata struct {
+	// Inputs are decoded from <source>_metadata.xml and describe the input files of the job.
+	Inputs []ams.AssetMetadata
+	// Outputs are decoded from <source>_manifest.xml and describe the encoded files,
+	// including the bitrates and the resolutions of their tracks.
+	Outputs []ams.AssetMetadata
+}
+
+// GetAssetMetadata downloads and decodes the input and output metadata files of an asset encoded by Media Encoder Standard.
+func GetAssetMetadata(ctx context.Context, client *ams.Client, assetID string) (*EncodedAssetMetadata, error) {
+	var metadata EncodedAssetMetadata
+	err := DownloadAssetFiles(ctx, client, assetID, isMetadataFile, func(assetFile ams.AssetFile, r io.Reader) error {
+		m, err := ams.ParseAssetMetadata(r)
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(assetFile.Name, outputMetadataSuffix) {
+			metadata.Outputs = append(metadata.Outputs, *m)
+		} else {
+			metadata.Inputs = append(metadata.Inputs, *m)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(metadata.Inputs) == 0 && len(metadata.Outputs) == 0 {
+		return nil, errors.Errorf("metadata not found. assetID='%v'", assetID)
+	}
+	return &metadata, nil
+}
+
+func isMetadataFile(assetFile ams.AssetFile) bool {
+	return strings.HasSuffix(assetFile.Name, inputMetadataSuffix) || strings.HasSuffix(assetFile.Name, outputMetadataSuffix)
+}
@@ -0,0 +1,89 @@
+package amsutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/recruit-tech/go-ams"
+)
+
+func TestGetAssetMetadata(t *testing.T) {
+	assetID := "nb:cid:UUID:encoded"
+	blobs := map[string]string{
+		"/container/small_metadata.xml": `<AssetFiles xmlns="http://schemas.microsoft.com/windowsazure/mediaservices/2014/07/mediaencoder/inputmetadata">` +
+			`<AssetFile Name="small.mp4" Size="3000000" Duration="PT5.016S"><VideoTracks><VideoTrack Id="1" Width="1920" Height="1080" Bitrate="4500" /></VideoTracks></AssetFile>` +
+			`</AssetFiles>`,
+		"/container/small_manifest.xml": `<AssetFiles xmlns="http://schemas.microsoft.com/windowsazure/mediaservices/2014/07/mediaencoder/outputmetadata">` +
+			`<AssetFile Name="small_1280x720_3400.mp4" Size="2250396" Duration="PT5.016S"><VideoTracks><VideoTrack Id="1" Width="1280" Height="720" TargetBitrate="3400" /></VideoTracks></AssetFile>` +
+			`<AssetFile Name="small_640x360_1000.mp4" Size="660000" Duration="PT5.016S"><VideoTracks><VideoTrack Id="1" Width="640" Height="360" TargetBitrate="1000" /></VideoTracks></AssetFile>` +
+			`</AssetFiles>`,
+	}
+
+	var s *httptest.Server
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/Assets('%v')/Files", assetID), func(w http.ResponseWriter, r *http.Request) {
+		assetFiles := []ams.AssetFile{
+			{Name: "small_1280x720_3400.mp4"},
+			{Name: "small_640x360_1000.mp4"},
+			{Name: "small.ism"},
+			{Name: "small_manifest.xml"},
+			{Name: "small_metadata.xml"},
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": assetFiles})
+	})
+	m.HandleFunc("/AccessPolicies", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(ams.AccessPolicy{ID: "download-policy-id"})
+	})
+	m.HandleFunc("/Locators", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(ams.Locator{ID: "download-locator-id", Path: s.URL + "/container?sig=signature", Type: ams.LocatorSAS})
+	})
+	m.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		blob, ok := blobs[r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, blob)
+	})
+	s = httptest.NewServer(m)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := GetAssetMetadata(context.TODO(), client, assetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var inputs, outputs []string
+	for _, m := range metadata.Inputs {
+		for _, assetFile := range m.AssetFiles {
+			inputs = append(inputs, fmt.Sprintf("%v %dx%d", assetFile.Name, assetFile.VideoTracks[0].Width, assetFile.VideoTracks[0].Height))
+		}
+	}
+	for _, m := range metadata.Outputs {
+		for _, assetFile := range m.AssetFiles {
+			outputs = append(outputs, fmt.Sprintf("%v %dx%d %dkbps", assetFile.Name, assetFile.VideoTracks[0].Width, assetFile.VideoTracks[0].Height, assetFile.VideoTracks[0].TargetBitrate))
+		}
+	}
+	if expected := []string{"small.mp4 1920x1080"}; !reflect.DeepEqual(inputs, expected) {
+		t.Errorf("unexpected inputs. expected: %v, actual: %v", expected, inputs)
+	}
+	if expected := []string{"small_1280x720_3400.mp4 1280x720 3400kbps", "small_640x360_1000.mp4 640x360 1000kbps"}; !reflect.DeepEqual(outputs, expected) {
+		t.Errorf("unexpected outputs. expected: %v, actual: %v", expected, outputs)
+	}
+}
@@ -0,0 +1,88 @@
+package amsutil
+
+import (
+	"time"
+
+	"github.com/recruit-tech/go-ams"
+)
+
+type options struct {
+	SHA256           func(name, sum string)
+	StorageSelector  StorageSelector
+	AssetName        string
+	AccessPolicyPool *AccessPolicyPool
+	LocatorID        string
+	LocatorName      string
+	Progress         func(job ams.Job, tasks []ams.Task)
+	MaxPollInterval  time.Duration
+}
+
+type option func(*options)
+
+func newOptions(opts []option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithSHA256Checksum calls fn with the name and the hex encoded SHA-256 digest of each uploaded file
+// after all of them are uploaded.
+func WithSHA256Checksum(fn func(name, sum string)) option {
+	return func(o *options) {
+		o.SHA256 = fn
+	}
+}
+
+// WithStorageSelector creates the asset in the storage account chosen by selector.
+func WithStorageSelector(selector StorageSelector) option {
+	return func(o *options) {
+		o.StorageSelector = selector
+	}
+}
+
+// WithAssetName names the uploaded asset instead of naming it after the file.
+func WithAssetName(name string) option {
+	return func(o *options) {
+		o.AssetName = name
+	}
+}
+
+// WithAccessPolicyPool shares access policies through pool instead of creating one per call.
+func WithAccessPolicyPool(pool *AccessPolicyPool) option {
+	return func(o *options) {
+		o.AccessPolicyPool = pool
+	}
+}
+
+// WithLocatorID publishes with a caller-chosen locator id, which keeps the streaming URL stable.
+// If the asset already has an on-demand origin locator with the id, it is reused.
+func WithLocatorID(locatorID string) option {
+	return func(o *options) {
+		o.LocatorID = locatorID
+	}
+}
+
+// WithLocatorName publishes with a named locator.
+// If the asset already has an on-demand origin locator with the name, it is reused.
+func WithLocatorName(name string) option {
+	return func(o *options) {
+		o.LocatorName = name
+	}
+}
+
+// WithProgress calls fn with the tasks of each processing job on every poll of WaitJob and WaitJobs.
+func WithProgress(fn func(job ams.Job, tasks []ams.Task)) option {
+	return func(o *options) {
+		o.Progress = fn
+	}
+}
+
+// WithMaxPollInterval caps the backoff of WaitJob and WaitJobs (default: 1 minute).
+// It must not be less than the interval given to them.
+func WithMaxPollInterval(d time.Duration) option {
+	return func(o *options) {
+		o.MaxPollInterval = d
+	}
+}
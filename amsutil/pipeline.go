@@ -0,0 +1,447 @@
+package amsutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/recruit-tech/go-ams"
+)
+
+// pipelineCancelPollInterval is the interval of polling the jobs canceled by Rollback.
+const pipelineCancelPollInterval = 5 * time.Second
+
+const (
+	PipelineResourceAsset   = "asset"
+	PipelineResourceJob     = "job"
+	PipelineResourceLocator = "locator"
+)
+
+// PipelineResource is a resource created by a step. AssetID is the asset a locator belongs to.
+type PipelineResource struct {
+	Step    string `json:"step"`
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	AssetID string `json:"assetId,omitempty"`
+}
+
+// PipelineState is the progress of a pipeline, which is saved to a PipelineStore whenever it changes.
+type PipelineState struct {
+	ID string `json:"id"`
+	// Completed holds the names of the completed steps.
+	Completed []string `json:"completed"`
+	// AssetID is the current asset, which is the input of the next step.
+	AssetID string `json:"assetId,omitempty"`
+	// URL is the streaming URL of the published asset.
+	URL string `json:"url,omitempty"`
+	// Resources holds the resources created by the pipeline in the order of creation.
+	Resources []PipelineResource `json:"resources"`
+}
+
+func (s *PipelineState) isCompleted(step string) bool {
+	for _, name := range s.Completed {
+		if name == step {
+			return true
+		}
+	}
+	return false
+}
+
+// FindResource returns the last resource of the type created by the step, or nil.
+func (s *PipelineState) FindResource(step, resourceType string) *PipelineResource {
+	for i := len(s.Resources) - 1; i >= 0; i-- {
+		if s.Resources[i].Step == step && s.Resources[i].Type == resourceType {
+			return &s.Resources[i]
+		}
+	}
+	return nil
+}
+
+// LastResource returns the last resource of the type created by any step, or nil.
+func (s *PipelineState) LastResource(resourceType string) *PipelineResource {
+	for i := len(s.Resources) - 1; i >= 0; i-- {
+		if s.Resources[i].Type == resourceType {
+			return &s.Resources[i]
+		}
+	}
+	return nil
+}
+
+// PipelineStore persists pipeline states. Load returns nil without error if the state doesn't exist.
+type PipelineStore interface {
+	Load(id string) (*PipelineState, error)
+	Save(state *PipelineState) error
+	Delete(id string) error
+}
+
+// PipelineStep is a step of a Pipeline. Name must be unique in the pipeline.
+// Run must record the resources it creates through PipelineRun.Update as soon as they exist,
+// so that a resumed run can pick them up and Rollback can delete them.
+// A resource whose id is only known after it is created should be named by PipelineResourceName,
+// so that a run resumed after a crash between the creation and the record can find it.
+type PipelineStep struct {
+	Name string
+	Run  func(ctx context.Context, run *PipelineRun) error
+}
+
+// PipelineRun gives a running step access to the client and the state.
+type PipelineRun struct {
+	client *ams.Client
+	store  PipelineStore
+	step   string
+	state  *PipelineState
+}
+
+func (r *PipelineRun) Client() *ams.Client {
+	return r.client
+}
+
+// State returns the current state. It must be changed through Update only.
+func (r *PipelineRun) State() *PipelineState {
+	return r.state
+}
+
+// Update changes the state by fn and saves it.
+func (r *PipelineRun) Update(fn func(state *PipelineState)) error {
+	fn(r.state)
+	if err := r.store.Save(r.state); err != nil {
+		return errors.Wrapf(err, "failed to save pipeline state. id='%v'", r.state.ID)
+	}
+	return nil
+}
+
+// Record adds the resource created by the running step and saves the state.
+func (r *PipelineRun) Record(resourceType, id, assetID string) error {
+	return r.Update(func(state *PipelineState) {
+		state.Resources = append(state.Resources, PipelineResource{Step: r.step, Type: resourceType, ID: id, AssetID: assetID})
+	})
+}
+
+// Pipeline runs steps in order and persists its state to store after each of them.
+// When it is run again with the same id after a failure or a restart, the completed steps are skipped.
+type Pipeline struct {
+	id     string
+	client *ams.Client
+	store  PipelineStore
+	steps  []PipelineStep
+}
+
+func NewPipeline(client *ams.Client, store PipelineStore, id string, steps ...PipelineStep) (*Pipeline, error) {
+	if client == nil {
+		return nil, errors.New("missing client")
+	}
+	if store == nil {
+		return nil, errors.New("missing store")
+	}
+	if len(id) == 0 {
+		return nil, errors.New("missing id")
+	}
+	names := make(map[string]bool)
+	for i, step := range steps {
+		if len(step.Name) == 0 {
+			return nil, errors.Errorf("steps[%d]: missing name", i)
+		}
+		if step.Run == nil {
+			return nil, errors.Errorf("steps[%d]: missing run", i)
+		}
+		if names[step.Name] {
+			return nil, errors.Errorf("duplicate step name '%v'", step.Name)
+		}
+		names[step.Name] = true
+	}
+	return &Pipeline{
+		id:     id,
+		client: client,
+		store:  store,
+		steps:  steps,
+	}, nil
+}
+
+func (p *Pipeline) loadState() (*PipelineState, error) {
+	state, err := p.store.Load(p.id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load pipeline state. id='%v'", p.id)
+	}
+	if state == nil {
+		state = &PipelineState{ID: p.id}
+	}
+	return state, nil
+}
+
+// Run runs the steps which haven't completed yet and returns the final state.
+// On error the state is kept in the store, and the pipeline can be resumed by Run or undone by Rollback.
+func (p *Pipeline) Run(ctx context.Context) (*PipelineState, error) {
+	if ctx == nil {
+		return nil, errors.New("missing ctx")
+	}
+	state, err := p.loadState()
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range p.steps {
+		if state.isCompleted(step.Name) {
+			continue
+		}
+		run := &PipelineRun{client: p.client, store: p.store, step: step.Name, state: state}
+		if err := step.Run(ctx, run); err != nil {
+			return state, errors.Wrapf(err, "step '%v' failed", step.Name)
+		}
+		err := run.Update(func(state *PipelineState) {
+			state.Completed = append(state.Completed, step.Name)
+		})
+		if err != nil {
+			return state, err
+		}
+	}
+	return state, nil
+}
+
+// PipelineResourceName is the name of the resources created by the step of the pipeline.
+func PipelineResourceName(pipelineID, step string) string {
+	return pipelineID + "/" + step
+}
+
+// Rollback cancels the running jobs of the pipeline, since their assets can't be deleted while they run,
+// and then deletes the resources created by the pipeline in the reverse order of creation.
+// The state is removed from the store once all of them are deleted; otherwise the remaining ones are kept for a retry.
+// Access policies created by PublishStep are left for RunJanitor.
+func (p *Pipeline) Rollback(ctx context.Context) error {
+	if ctx == nil {
+		return errors.New("missing ctx")
+	}
+	state, err := p.loadState()
+	if err != nil {
+		return err
+	}
+
+	if err := cancelPipelineJobs(ctx, p.client, state.Resources); err != nil {
+		return errors.Wrapf(err, "failed to rollback pipeline. id='%v'", p.id)
+	}
+
+	var (
+		remaining []PipelineResource
+		errs      []string
+	)
+	for i := len(state.Resources) - 1; i >= 0; i-- {
+		resource := state.Resources[i]
+		if err := deletePipelineResource(ctx, p.client, resource); err != nil {
+			remaining = append([]PipelineResource{resource}, remaining...)
+			errs = append(errs, fmt.Sprintf("%v[#%v]: %v", resource.Type, resource.ID, err))
+		}
+	}
+	if len(errs) != 0 {
+		state.Resources = remaining
+		if err := p.store.Save(state); err != nil {
+			return errors.Wrapf(err, "failed to save pipeline state. id='%v'", p.id)
+		}
+		return errors.Errorf("failed to rollback pipeline. id='%v': %v", p.id, strings.Join(errs, ", "))
+	}
+	if err := p.store.Delete(p.id); err != nil {
+		return errors.Wrapf(err, "failed to delete pipeline state. id='%v'", p.id)
+	}
+	return nil
+}
+
+// cancelPipelineJobs cancels the jobs which haven't finished and waits until they are canceled.
+func cancelPipelineJobs(ctx context.Context, client *ams.Client, resources []PipelineResource) error {
+	var canceled []string
+	for _, resource := range resources {
+		if resource.Type != PipelineResourceJob {
+			continue
+		}
+		job, err := client.GetJob(ctx, resource.ID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get job. jobID='%v'", resource.ID)
+		}
+		if isFinalJobState(job.State) {
+			continue
+		}
+		if err := client.CancelJob(ctx, job.ID); err != nil {
+			return errors.Wrapf(err, "failed to cancel job. jobID='%v'", job.ID)
+		}
+		canceled = append(canceled, job.ID)
+	}
+	if len(canceled) == 0 {
+		return nil
+	}
+	_, err := WaitJobs(ctx, client, canceled, pipelineCancelPollInterval)
+	if _, ok := err.(*JobFailedError); ok {
+		return nil
+	}
+	return err
+}
+
+func deletePipelineResource(ctx context.Context, client *ams.Client, resource PipelineResource) error {
+	switch resource.Type {
+	case PipelineResourceAsset:
+		return client.DeleteAsset(ctx, resource.ID)
+	case PipelineResourceJob:
+		return client.DeleteJob(ctx, resource.ID)
+	case PipelineResourceLocator:
+		// the locator is recorded before it is created, so it may not exist
+		locators, err := client.GetLocatorsWithAsset(ctx, resource.AssetID)
+		if err != nil {
+			return err
+		}
+		for _, locator := range locators {
+			if strings.TrimPrefix(locator.ID, ams.LocatorIDPrefix) == strings.TrimPrefix(resource.ID, ams.LocatorIDPrefix) {
+				return client.DeleteLocator(ctx, locator.ID)
+			}
+		}
+		return nil
+	default:
+		return errors.Errorf("unknown resource type '%v'", resource.Type)
+	}
+}
+
+// UploadStep uploads the video file at filename as a new asset named by PipelineResourceName, which becomes the current asset.
+// An asset left by an interrupted upload is found by the name, deleted and uploaded again,
+// so the file must still exist when the pipeline is resumed.
+func UploadStep(name, filename string, chunkSize int64, workers uint, opts ...option) PipelineStep {
+	return PipelineStep{
+		Name: name,
+		Run: func(ctx context.Context, run *PipelineRun) error {
+			if asset := run.State().FindResource(name, PipelineResourceAsset); asset != nil {
+				assetID := asset.ID
+				return run.Update(func(state *PipelineState) {
+					state.AssetID = assetID
+				})
+			}
+
+			client := run.Client()
+			assetName := PipelineResourceName(run.State().ID, name)
+			leftovers, err := client.GetAssetsWithName(ctx, assetName)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get assets. name='%v'", assetName)
+			}
+			for _, leftover := range leftovers {
+				if err := client.DeleteAsset(ctx, leftover.ID); err != nil {
+					return errors.Wrapf(err, "failed to delete interrupted upload. assetID='%v'", leftover.ID)
+				}
+			}
+
+			file, err := os.Open(filename)
+			if err != nil {
+				return errors.Wrapf(err, "failed to open file. filename='%v'", filename)
+			}
+			defer file.Close()
+
+			asset, err := UploadFile(ctx, client, file, chunkSize, workers, append(opts[:len(opts):len(opts)], WithAssetName(assetName))...)
+			if err != nil {
+				return err
+			}
+			return run.Update(func(state *PipelineState) {
+				state.Resources = append(state.Resources, PipelineResource{Step: name, Type: PipelineResourceAsset, ID: asset.ID})
+				state.AssetID = asset.ID
+			})
+		},
+	}
+}
+
+// SourceAssetStep makes an existing asset the current asset. Rollback doesn't delete it.
+func SourceAssetStep(name, assetID string) PipelineStep {
+	return PipelineStep{
+		Name: name,
+		Run: func(ctx context.Context, run *PipelineRun) error {
+			if len(assetID) == 0 {
+				return errors.New("missing assetID")
+			}
+			return run.Update(func(state *PipelineState) {
+				state.AssetID = assetID
+			})
+		},
+	}
+}
+
+// EncodeStep submits an encoding job named by PipelineResourceName for the current asset,
+// and its output asset becomes the current asset. A job submitted by an interrupted run is found by the name instead of submitted again.
+// It doesn't wait for the job; add WaitJobStep after it.
+func EncodeStep(name, mediaProcessorID, configuration string, opts ...ams.JobOption) PipelineStep {
+	return PipelineStep{
+		Name: name,
+		Run: func(ctx context.Context, run *PipelineRun) error {
+			if run.State().FindResource(name, PipelineResourceJob) != nil {
+				return nil
+			}
+
+			client := run.Client()
+			jobName := PipelineResourceName(run.State().ID, name)
+			jobs, err := client.GetJobs(ctx, ams.SetJobNames(jobName))
+			if err != nil {
+				return errors.Wrapf(err, "failed to get jobs. name='%v'", jobName)
+			}
+			var (
+				job     *ams.Job
+				outputs []ams.Asset
+			)
+			if len(jobs) != 0 {
+				job = &jobs[0]
+				outputs, err = client.GetOutputMediaAssets(ctx, job.ID)
+				if err != nil {
+					return errors.Wrapf(err, "failed to get output media assets. jobID='%v'", job.ID)
+				}
+			} else {
+				outputs, job, err = Encode(ctx, client, run.State().AssetID, mediaProcessorID, configuration, append(opts[:len(opts):len(opts)], ams.SetJobName(jobName))...)
+				if err != nil {
+					return err
+				}
+			}
+			return run.Update(func(state *PipelineState) {
+				state.Resources = append(state.Resources, PipelineResource{Step: name, Type: PipelineResourceJob, ID: job.ID})
+				for _, output := range outputs {
+					state.Resources = append(state.Resources, PipelineResource{Step: name, Type: PipelineResourceAsset, ID: output.ID})
+				}
+				if len(outputs) != 0 {
+					state.AssetID = outputs[0].ID
+				}
+			})
+		},
+	}
+}
+
+// WaitJobStep waits for the last job submitted by the pipeline.
+func WaitJobStep(name string, interval time.Duration, opts ...option) PipelineStep {
+	return PipelineStep{
+		Name: name,
+		Run: func(ctx context.Context, run *PipelineRun) error {
+			job := run.State().LastResource(PipelineResourceJob)
+			if job == nil {
+				return errors.New("no job to wait for")
+			}
+			return WaitJob(ctx, run.Client(), job.ID, interval, opts...)
+		},
+	}
+}
+
+// PublishStep publishes the current asset for minutes and saves the streaming URL to the state.
+// The locator id is chosen and recorded before publishing, so a resumed run reuses the locator.
+func PublishStep(name string, minutes float64, opts ...option) PipelineStep {
+	return PipelineStep{
+		Name: name,
+		Run: func(ctx context.Context, run *PipelineRun) error {
+			assetID := run.State().AssetID
+			locator := run.State().FindResource(name, PipelineResourceLocator)
+			if locator == nil {
+				locatorID, err := ams.NewLocatorID()
+				if err != nil {
+					return errors.Wrap(err, "failed to generate locator id")
+				}
+				if err := run.Record(PipelineResourceLocator, locatorID, assetID); err != nil {
+					return err
+				}
+				locator = run.State().FindResource(name, PipelineResourceLocator)
+			}
+
+			u, err := Publish(ctx, run.Client(), assetID, minutes, append(opts[:len(opts):len(opts)], WithLocatorID(locator.ID))...)
+			if err != nil {
+				return err
+			}
+			return run.Update(func(state *PipelineState) {
+				state.URL = u
+			})
+		},
+	}
+}
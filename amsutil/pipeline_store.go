@@ -0,0 +1,83 @@
+package amsutil
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// FilePipelineStore stores each pipeline state as a JSON file in a directory.
+type FilePipelineStore struct {
+	dir string
+}
+
+func NewFilePipelineStore(dir string) (*FilePipelineStore, error) {
+	if len(dir) == 0 {
+		return nil, errors.New("missing dir")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory. dir='%v'", dir)
+	}
+	return &FilePipelineStore{dir: dir}, nil
+}
+
+func (s *FilePipelineStore) filename(id string) string {
+	return filepath.Join(s.dir, url.PathEscape(id)+".json")
+}
+
+func (s *FilePipelineStore) Load(id string) (*PipelineState, error) {
+	b, err := ioutil.ReadFile(s.filename(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read file")
+	}
+	var state PipelineState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil, errors.Wrap(err, "failed to json.Unmarshal state")
+	}
+	return &state, nil
+}
+
+// Save writes the state to a temporary file and renames it, so a crash never leaves a partial file.
+func (s *FilePipelineStore) Save(state *PipelineState) error {
+	if state == nil {
+		return errors.New("missing state")
+	}
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to json.Marshal state")
+	}
+	tmp, err := ioutil.TempFile(s.dir, ".pipeline")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "failed to write file")
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "failed to sync file")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to close file")
+	}
+	if err := os.Rename(tmp.Name(), s.filename(state.ID)); err != nil {
+		return errors.Wrap(err, "failed to rename file")
+	}
+	return nil
+}
+
+func (s *FilePipelineStore) Delete(id string) error {
+	if err := os.Remove(s.filename(id)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove file")
+	}
+	return nil
+}
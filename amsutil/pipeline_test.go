@@ -0,0 +1,407 @@
+package amsutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/recruit-tech/go-ams"
+)
+
+func TestPipeline(t *testing.T) {
+	var deleted []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/Jobs('job')", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted = append(deleted, "job")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		json.NewEncoder(w).Encode(ams.Job{ID: "job", State: ams.JobFinished})
+	})
+	mux.HandleFunc("/Assets('output')/Locators", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": []ams.Locator{{ID: ams.LocatorIDPrefix + "locator"}}})
+	})
+	mux.HandleFunc("/Locators('nb:lid:UUID:locator')", func(w http.ResponseWriter, r *http.Request) {
+		deleted = append(deleted, "locator")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	for _, assetID := range []string{"input", "output"} {
+		assetID := assetID
+		mux.HandleFunc("/Assets('"+assetID+"')", func(w http.ResponseWriter, r *http.Request) {
+			deleted = append(deleted, assetID)
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "pipeline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewFilePipelineStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runs := make(map[string]int)
+	fail := true
+	steps := []PipelineStep{
+		{
+			Name: "upload",
+			Run: func(ctx context.Context, run *PipelineRun) error {
+				runs["upload"]++
+				return run.Update(func(state *PipelineState) {
+					state.Resources = append(state.Resources, PipelineResource{Step: "upload", Type: PipelineResourceAsset, ID: "input"})
+					state.AssetID = "input"
+				})
+			},
+		},
+		{
+			Name: "encode",
+			Run: func(ctx context.Context, run *PipelineRun) error {
+				runs["encode"]++
+				if run.State().FindResource("encode", PipelineResourceJob) == nil {
+					if err := run.Record(PipelineResourceJob, "job", ""); err != nil {
+						return err
+					}
+					if err := run.Record(PipelineResourceAsset, "output", ""); err != nil {
+						return err
+					}
+				}
+				if fail {
+					return errors.New("crash")
+				}
+				return run.Update(func(state *PipelineState) {
+					state.AssetID = "output"
+				})
+			},
+		},
+		{
+			Name: "publish",
+			Run: func(ctx context.Context, run *PipelineRun) error {
+				runs["publish"]++
+				if err := run.Record(PipelineResourceLocator, "locator", run.State().AssetID); err != nil {
+					return err
+				}
+				return run.Update(func(state *PipelineState) {
+					state.URL = "https://example.com/manifest"
+				})
+			},
+		},
+	}
+
+	pipeline, err := NewPipeline(client, store, "pipeline/1", steps...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pipeline.Run(context.TODO()); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// resume with a new pipeline, as if the process restarted
+	fail = false
+	pipeline, err = NewPipeline(client, store, "pipeline/1", steps...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := pipeline.Run(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]int{"upload": 1, "encode": 2, "publish": 1}; !reflect.DeepEqual(runs, expected) {
+		t.Errorf("unexpected runs. expected: %v, actual: %v", expected, runs)
+	}
+	if state.URL != "https://example.com/manifest" || state.AssetID != "output" {
+		t.Errorf("unexpected state: %#v", state)
+	}
+	if len(state.Resources) != 4 {
+		t.Errorf("unexpected resources: %#v", state.Resources)
+	}
+
+	saved, err := store.Load("pipeline/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(saved, state) {
+		t.Errorf("unexpected saved state. expected: %#v, actual: %#v", state, saved)
+	}
+
+	if err := pipeline.Rollback(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"locator", "output", "job", "input"}; !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("unexpected deleted. expected: %v, actual: %v", expected, deleted)
+	}
+	if saved, err := store.Load("pipeline/1"); err != nil || saved != nil {
+		t.Errorf("state must be deleted. state: %#v, err: %v", saved, err)
+	}
+}
+
+func TestNewPipeline_duplicateStep(t *testing.T) {
+	client, err := ams.NewClient("http://example.com", http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := SourceAssetStep("source", "asset")
+	if _, err := NewPipeline(client, &FilePipelineStore{dir: os.TempDir()}, "id", step, step); err == nil {
+		t.Error("expected error")
+	}
+}
+
+var pipelineFakeIDPattern = regexp.MustCompile(`\('[^']*'\)`)
+
+// pipelineFakeAMS serves the requests of UploadStep, EncodeStep, PublishStep and Rollback,
+// and fails the requests in fail to simulate a crash. A key without ids, e.g. "DELETE /Locators", fails all of them.
+type pipelineFakeAMS struct {
+	t      *testing.T
+	m      sync.Mutex
+	url    string
+	serial int
+	fail   map[string]bool
+
+	assets   map[string]ams.Asset
+	jobs     map[string]ams.Job
+	outputs  map[string]string
+	locators map[string]ams.Locator
+	created  map[string]int
+	requests []string
+}
+
+func (f *pipelineFakeAMS) newID(prefix string) string {
+	f.serial++
+	return fmt.Sprintf("%s-%d", prefix, f.serial)
+}
+
+func (f *pipelineFakeAMS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if strings.HasPrefix(r.URL.Path, "/blob/") {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	request := r.Method + " " + r.URL.Path
+	f.requests = append(f.requests, request)
+	if f.fail[request] || f.fail[r.Method+" "+pipelineFakeIDPattern.ReplaceAllString(r.URL.Path, "")] {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var params map[string]interface{}
+	if r.Method == http.MethodPost || r.Method == "MERGE" {
+		json.NewDecoder(r.Body).Decode(&params)
+	}
+	writeValue := func(value interface{}) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": value})
+	}
+	created := func(v interface{}) {
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(v)
+	}
+	filter := r.URL.Query().Get("$filter")
+
+	switch {
+	case request == "GET /Assets":
+		var value []ams.Asset
+		for _, asset := range f.assets {
+			if strings.Contains(filter, "'"+asset.Name+"'") {
+				value = append(value, asset)
+			}
+		}
+		writeValue(value)
+	case request == "POST /Assets":
+		asset := ams.Asset{ID: f.newID("asset"), Name: params["Name"].(string)}
+		f.assets[asset.ID] = asset
+		f.created["asset"]++
+		created(asset)
+	case request == "POST /Files":
+		created(ams.AssetFile{ID: f.newID("file"), Name: params["Name"].(string), ParentAssetID: params["ParentAssetId"].(string)})
+	case r.Method == "MERGE" && strings.HasPrefix(r.URL.Path, "/Files("):
+		w.WriteHeader(http.StatusNoContent)
+	case request == "POST /AccessPolicies":
+		created(ams.AccessPolicy{ID: f.newID("policy")})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/AccessPolicies('"):
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/AccessPolicies('"), "')")
+		json.NewEncoder(w).Encode(ams.AccessPolicy{ID: id, DurationInMinutes: 365 * 24 * 60})
+	case request == "POST /Locators":
+		locator := ams.Locator{AssetID: params["AssetId"].(string), Type: int(params["Type"].(float64)), AccessPolicyID: params["AccessPolicyId"].(string)}
+		if startTime, ok := params["StartTime"].(string); ok {
+			locator.StartTime = startTime
+		}
+		if id, ok := params["Id"].(string); ok {
+			locator.ID = id
+		} else {
+			locator.ID = ams.LocatorIDPrefix + f.newID("locator")
+		}
+		if locator.Type == ams.LocatorSAS {
+			locator.Path = f.url + "/blob/container?sig=signature"
+		} else {
+			locator.Path = "https://streaming.example.com/" + strings.TrimPrefix(locator.ID, ams.LocatorIDPrefix) + "/"
+			f.created["locator"]++
+		}
+		f.locators[locator.ID] = locator
+		created(locator)
+	case request == "GET /Jobs":
+		var value []ams.Job
+		for _, job := range f.jobs {
+			if strings.Contains(filter, "'"+job.Name+"'") || strings.Contains(filter, "'"+job.ID+"'") {
+				value = append(value, job)
+			}
+		}
+		writeValue(value)
+	case request == "POST /Jobs":
+		job := ams.Job{ID: f.newID("job"), Name: params["Name"].(string), State: ams.JobProcessing}
+		output := ams.Asset{ID: f.newID("output")}
+		f.jobs[job.ID] = job
+		f.assets[output.ID] = output
+		f.outputs[job.ID] = output.ID
+		f.created["job"]++
+		created(job)
+	case request == "GET /CancelJob":
+		jobID := strings.Trim(r.URL.Query().Get("jobid"), "'")
+		job := f.jobs[jobID]
+		job.State = ams.JobCanceled
+		f.jobs[jobID] = job
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	case strings.HasSuffix(r.URL.Path, "/OutputMediaAssets"):
+		jobID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/Jobs('"), "')/OutputMediaAssets")
+		writeValue([]ams.Asset{f.assets[f.outputs[jobID]]})
+	case strings.HasPrefix(r.URL.Path, "/Jobs('"):
+		json.NewEncoder(w).Encode(f.jobs[strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/Jobs('"), "')")])
+	case strings.HasSuffix(r.URL.Path, "/Locators"):
+		assetID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/Assets('"), "')/Locators")
+		var value []ams.Locator
+		for _, locator := range f.locators {
+			if locator.AssetID == assetID {
+				value = append(value, locator)
+			}
+		}
+		writeValue(value)
+	case r.Method == "MERGE" && strings.HasPrefix(r.URL.Path, "/Locators("):
+		w.WriteHeader(http.StatusNoContent)
+	case strings.HasSuffix(r.URL.Path, "/Files"):
+		writeValue([]ams.AssetFile{{Name: "video.ism"}})
+	case strings.HasPrefix(r.URL.Path, "/Assets('"):
+		json.NewEncoder(w).Encode(f.assets[strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/Assets('"), "')")])
+	default:
+		f.t.Errorf("unexpected request: %v", request)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *pipelineFakeAMS) count(request string) int {
+	n := 0
+	for _, r := range f.requests {
+		if r == request {
+			n++
+		}
+	}
+	return n
+}
+
+func TestPipeline_resumeSteps(t *testing.T) {
+	f := &pipelineFakeAMS{
+		t:        t,
+		assets:   make(map[string]ams.Asset),
+		jobs:     make(map[string]ams.Job),
+		outputs:  make(map[string]string),
+		locators: make(map[string]ams.Locator),
+		created:  make(map[string]int),
+	}
+	s := httptest.NewServer(f)
+	defer s.Close()
+	f.url = s.URL
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "pipeline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewFilePipelineStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "video.mp4")
+	if err := ioutil.WriteFile(filename, []byte("video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pipeline, err := NewPipeline(client, store, "pipeline",
+		UploadStep("upload", filename, 1024, 1),
+		EncodeStep("encode", "media-processor", "preset"),
+		PublishStep("publish", 60),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each run crashes after a step created a resource, before it is recorded.
+	// Publish deletes the locator it created on failure, which doesn't happen on a crash.
+	crashes := []map[string]bool{
+		{"POST /Files": true},
+		{"GET /Jobs/OutputMediaAssets": true},
+		{"GET /Assets/Files": true, "DELETE /Locators": true},
+	}
+	for i, fail := range crashes {
+		f.fail = fail
+		if _, err := pipeline.Run(context.TODO()); err == nil {
+			t.Fatalf("run %d: expected error", i)
+		}
+	}
+	f.fail = nil
+	state, err := pipeline.Run(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := f.count("DELETE /Assets('asset-1')"); n != 1 {
+		t.Errorf("the asset of the interrupted upload must be deleted once, but %d", n)
+	}
+	if expected := map[string]int{"asset": 2, "job": 1, "locator": 1}; !reflect.DeepEqual(f.created, expected) {
+		t.Errorf("unexpected created resources. expected: %v, actual: %v", expected, f.created)
+	}
+	job := state.FindResource("encode", PipelineResourceJob)
+	if job == nil || state.AssetID != f.outputs[job.ID] || len(state.URL) == 0 {
+		t.Errorf("unexpected state: %#v", state)
+	}
+
+	if err := pipeline.Rollback(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	var rollback []string
+	for _, request := range f.requests {
+		if strings.HasPrefix(request, "GET /CancelJob") || strings.HasPrefix(request, "DELETE") {
+			rollback = append(rollback, pipelineFakeIDPattern.ReplaceAllString(request, ""))
+		}
+	}
+	expected := []string{"GET /CancelJob", "DELETE /Locators", "DELETE /Assets", "DELETE /Jobs", "DELETE /Assets"}
+	if actual := rollback[len(rollback)-len(expected):]; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected rollback requests. expected: %v, actual: %v", expected, actual)
+	}
+}
@@ -11,11 +11,9 @@ import (
 	"github.com/recruit-tech/go-ams"
 )
 
-const (
-	publishAccessPolicyName = "ViewPolicy"
-)
+const publishAccessPolicyName = "ViewPolicy"
 
-func Publish(ctx context.Context, client *ams.Client, assetID string, minutes float64) (string, error) {
+func Publish(ctx context.Context, client *ams.Client, assetID string, minutes float64, opts ...option) (string, error) {
 	if ctx == nil {
 		return "", errors.New("missing ctx")
 	}
@@ -34,28 +32,52 @@ func Publish(ctx context.Context, client *ams.Client, assetID string, minutes fl
 		return "", errors.Wrapf(err, "failed to get asset. assetID='%v'", assetID)
 	}
 
+	options := newOptions(opts)
 	success := false
 
-	accessPolicy, err := client.CreateAccessPolicy(ctx, publishAccessPolicyName, minutes, ams.PermissionRead)
+	locator, err := findPublishedLocator(ctx, client, asset.ID, options)
 	if err != nil {
-		return "", errors.Wrap(err, "failed to create access policy")
+		return "", err
 	}
-	defer func() {
-		if !success {
-			client.DeleteAccessPolicy(ctx, accessPolicy.ID)
+	if locator == nil {
+		accessPolicy, release, err := acquireAccessPolicy(ctx, client, options.AccessPolicyPool, publishAccessPolicyName, minutes, ams.PermissionRead)
+		if err != nil {
+			return "", err
 		}
-	}()
+		defer func() {
+			// a pooled access policy is kept while the published locator uses it
+			if !success || options.AccessPolicyPool != nil {
+				release()
+			}
+		}()
 
-	startTime := TimeNow().Add(-5 * time.Minute)
-	locator, err := client.CreateLocator(ctx, accessPolicy.ID, asset.ID, startTime, ams.LocatorOnDemandOrigin)
-	if err != nil {
-		return "", errors.Wrap(err, "failed to create locator")
-	}
-	defer func() {
-		if !success {
-			client.DeleteLocator(ctx, locator.ID)
+		var locatorOpts []ams.LocatorOption
+		if len(options.LocatorID) != 0 {
+			locatorOpts = append(locatorOpts, ams.SetLocatorID(options.LocatorID))
+		}
+		if len(options.LocatorName) != 0 {
+			locatorOpts = append(locatorOpts, ams.SetLocatorName(options.LocatorName))
+		}
+		startTime := TimeNow().Add(-5 * time.Minute)
+		locator, err = client.CreateLocator(ctx, accessPolicy.ID, asset.ID, startTime, ams.LocatorOnDemandOrigin, locatorOpts...)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to create locator")
+		}
+		defer func() {
+			if !success {
+				client.DeleteLocator(ctx, locator.ID)
+			}
+		}()
+	} else {
+		// the reused locator may expire before the minutes requested this time
+		expirationDateTime := TimeNow().Add(time.Duration(minutes * float64(time.Minute)))
+		if err := checkLocatorExpiration(ctx, client, *locator, expirationDateTime, make(map[string]*ams.AccessPolicy)); err != nil {
+			return "", err
+		}
+		if err := client.UpdateLocator(ctx, locator.ID, time.Time{}, expirationDateTime); err != nil {
+			return "", errors.Wrapf(err, "failed to update locator. locatorID='%v'", locator.ID)
 		}
-	}()
+	}
 
 	assetFiles, err := client.GetAssetFiles(ctx, asset.ID)
 	if err != nil {
@@ -82,6 +104,60 @@ func Publish(ctx context.Context, client *ams.Client, assetID string, minutes fl
 	return u.String(), nil
 }
 
+// findPublishedLocator returns the on-demand origin locator of the asset which has the id or name given by options,
+// so that publishing again reuses it instead of creating a duplicate.
+func findPublishedLocator(ctx context.Context, client *ams.Client, assetID string, options *options) (*ams.Locator, error) {
+	if len(options.LocatorID) == 0 && len(options.LocatorName) == 0 {
+		return nil, nil
+	}
+	locators, err := client.GetLocatorsWithAsset(ctx, assetID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get locators. assetID='%v'", assetID)
+	}
+	for _, locator := range locators {
+		if locator.Type != ams.LocatorOnDemandOrigin {
+			continue
+		}
+		if len(options.LocatorID) != 0 && strings.TrimPrefix(locator.ID, ams.LocatorIDPrefix) != strings.TrimPrefix(options.LocatorID, ams.LocatorIDPrefix) {
+			continue
+		}
+		if len(options.LocatorName) != 0 && locator.Name != options.LocatorName {
+			continue
+		}
+		return &locator, nil
+	}
+	return nil, nil
+}
+
+// checkLocatorExpiration fails if expirationDateTime is past the StartTime of locator plus the duration of its access policy.
+// A locator without StartTime is checked from the creation of its access policy, which precedes the locator.
+// accessPolicies caches the access policies by id.
+func checkLocatorExpiration(ctx context.Context, client *ams.Client, locator ams.Locator, expirationDateTime time.Time, accessPolicies map[string]*ams.AccessPolicy) error {
+	accessPolicy, ok := accessPolicies[locator.AccessPolicyID]
+	if !ok {
+		var err error
+		accessPolicy, err = client.GetAccessPolicy(ctx, locator.AccessPolicyID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get access policy. accessPolicyID='%v'", locator.AccessPolicyID)
+		}
+		accessPolicies[locator.AccessPolicyID] = accessPolicy
+	}
+
+	startTime, err := parseTime(locator.StartTime)
+	if err != nil {
+		startTime, err = parseTime(accessPolicy.Created)
+		if err != nil {
+			return errors.Errorf("unknown start time of locator. locatorID='%v'", locator.ID)
+		}
+	}
+	maxExpiration := startTime.Add(time.Duration(accessPolicy.DurationInMinutes * float64(time.Minute)))
+	if expirationDateTime.After(maxExpiration) {
+		return errors.Errorf("expiration exceeds the duration of the access policy. locatorID='%v', accessPolicyID='%v', expiration='%v', max='%v'",
+			locator.ID, accessPolicy.ID, expirationDateTime.UTC().Format(time.RFC3339), maxExpiration.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
 func findAssetManifest(assetFiles []ams.AssetFile) *ams.AssetFile {
 	for _, assetFile := range assetFiles {
 		if strings.HasSuffix(assetFile.Name, ".ism") {
@@ -90,3 +166,50 @@ func findAssetManifest(assetFiles []ams.AssetFile) *ams.AssetFile {
 	}
 	return nil
 }
+
+// RenewLocators extends the expiration of every on-demand origin locator of the asset to minutes from now.
+// The locators are updated in place, so their streaming URLs stay the same.
+// AMS rejects an expiration past the StartTime of a locator plus the duration of its access policy,
+// so RenewLocators fails without updating any locator if one of them can't be extended that far.
+func RenewLocators(ctx context.Context, client *ams.Client, assetID string, minutes float64) ([]ams.Locator, error) {
+	if ctx == nil {
+		return nil, errors.New("missing ctx")
+	}
+	if client == nil {
+		return nil, errors.New("missing client")
+	}
+	if len(assetID) == 0 {
+		return nil, errors.New("missing assetID")
+	}
+	if minutes <= 0 {
+		return nil, errors.New("minutes must be greater than 0")
+	}
+
+	locators, err := client.GetLocatorsWithAsset(ctx, assetID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get locators. assetID='%v'", assetID)
+	}
+
+	expirationDateTime := TimeNow().Add(time.Duration(minutes * float64(time.Minute)))
+	var targets []ams.Locator
+	accessPolicies := make(map[string]*ams.AccessPolicy)
+	for _, locator := range locators {
+		if locator.Type != ams.LocatorOnDemandOrigin {
+			continue
+		}
+		if err := checkLocatorExpiration(ctx, client, locator, expirationDateTime, accessPolicies); err != nil {
+			return nil, err
+		}
+		targets = append(targets, locator)
+	}
+
+	var renewed []ams.Locator
+	for _, locator := range targets {
+		if err := client.UpdateLocator(ctx, locator.ID, time.Time{}, expirationDateTime); err != nil {
+			return nil, errors.Wrapf(err, "failed to update locator. locatorID='%v'", locator.ID)
+		}
+		locator.ExpirationDateTime = expirationDateTime.UTC().Format(time.RFC3339)
+		renewed = append(renewed, locator)
+	}
+	return renewed, nil
+}
@@ -0,0 +1,146 @@
+package amsutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/recruit-tech/go-ams"
+)
+
+func TestRenewLocators(t *testing.T) {
+	now := time.Date(2017, 8, 10, 0, 0, 0, 0, time.UTC)
+	TimeNow = func() time.Time { return now }
+	defer func() { TimeNow = time.Now }()
+
+	assetID := "sample-asset-id"
+	locators := []ams.Locator{
+		{ID: "origin-locator-id", Type: ams.LocatorOnDemandOrigin, AssetID: assetID, AccessPolicyID: "long-policy-id", StartTime: "2017-08-01T00:00:00Z"},
+		{ID: "sas-locator-id", Type: ams.LocatorSAS, AssetID: assetID},
+	}
+	var updated []string
+
+	m := http.NewServeMux()
+	m.HandleFunc("/AccessPolicies('long-policy-id')", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ams.AccessPolicy{ID: "long-policy-id", DurationInMinutes: 30 * 24 * 60})
+	})
+	m.HandleFunc(fmt.Sprintf("/Assets('%v')/Locators", assetID), func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": locators})
+	})
+	for _, locator := range locators {
+		id := locator.ID
+		m.HandleFunc(fmt.Sprintf("/Locators('%v')", id), func(w http.ResponseWriter, r *http.Request) {
+			var params map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+				t.Fatal(err)
+			}
+			if expected := "2017-08-11T00:00:00Z"; params["ExpirationDateTime"] != expected {
+				t.Errorf("unexpected ExpirationDateTime. expected: %v, actual: %v", expected, params["ExpirationDateTime"])
+			}
+			updated = append(updated, id)
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renewed, err := RenewLocators(context.TODO(), client, assetID, 24*60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 1 || updated[0] != "origin-locator-id" {
+		t.Errorf("unexpected updated locators: %v", updated)
+	}
+	if len(renewed) != 1 || renewed[0].ExpirationDateTime != "2017-08-11T00:00:00Z" {
+		t.Errorf("unexpected renewed locators: %#v", renewed)
+	}
+
+	// the long policy ends at 2017-08-31T00:00:00Z
+	updated = nil
+	if _, err := RenewLocators(context.TODO(), client, assetID, 30*24*60); err == nil {
+		t.Error("expected error for expiration past the access policy")
+	}
+	if len(updated) != 0 {
+		t.Errorf("update locator which can't be renewed: %v", updated)
+	}
+}
+
+func TestPublish_reuseLocator(t *testing.T) {
+	now := time.Date(2017, 8, 10, 0, 0, 0, 0, time.UTC)
+	TimeNow = func() time.Time { return now }
+	defer func() { TimeNow = time.Now }()
+
+	assetID := "sample-asset-id"
+	locatorName := "published"
+	var created bool
+	var updated int
+
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/Assets('%v')", assetID), func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ams.Asset{ID: assetID})
+	})
+	m.HandleFunc(fmt.Sprintf("/Assets('%v')/Locators", assetID), func(w http.ResponseWriter, r *http.Request) {
+		locators := []ams.Locator{
+			{ID: "nb:lid:UUID:sas", Type: ams.LocatorSAS, Name: locatorName, Path: "https://fake.blob.url/sas"},
+			{ID: "nb:lid:UUID:origin", Type: ams.LocatorOnDemandOrigin, Name: locatorName, Path: "https://fake.streaming.url/origin/", AccessPolicyID: "view-policy-id", StartTime: "2017-08-01T00:00:00Z"},
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": locators})
+	})
+	m.HandleFunc("/AccessPolicies('view-policy-id')", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ams.AccessPolicy{ID: "view-policy-id", DurationInMinutes: 30 * 24 * 60})
+	})
+	m.HandleFunc(fmt.Sprintf("/Assets('%v')/Files", assetID), func(w http.ResponseWriter, r *http.Request) {
+		assetFiles := []ams.AssetFile{{Name: "video.ism"}, {Name: "video.mp4"}}
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": assetFiles})
+	})
+	m.HandleFunc("/Locators", func(w http.ResponseWriter, r *http.Request) {
+		created = true
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	m.HandleFunc("/Locators('nb:lid:UUID:origin')", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "MERGE" {
+			t.Errorf("unexpected method: %v", r.Method)
+		}
+		var params map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatal(err)
+		}
+		if expected := "2017-08-10T01:00:00Z"; params["ExpirationDateTime"] != expected {
+			t.Errorf("unexpected ExpirationDateTime. expected: %v, actual: %v", expected, params["ExpirationDateTime"])
+		}
+		updated++
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opt := range []option{WithLocatorName(locatorName), WithLocatorID("origin")} {
+		u, err := Publish(context.TODO(), client, assetID, 60, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := "https://fake.streaming.url/origin/video.ism/manifest"; u != expected {
+			t.Errorf("unexpected url. expected: %v, actual: %v", expected, u)
+		}
+	}
+	if created {
+		t.Error("create duplicate locator")
+	}
+	if updated != 2 {
+		t.Errorf("reused locator not extended. updated: %v", updated)
+	}
+}
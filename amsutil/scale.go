@@ -0,0 +1,109 @@
+package amsutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/recruit-tech/go-ams"
+)
+
+const restoreReservedUnitsTimeout = time.Minute
+
+// ScaleForJobs raises the encoding reserved units of the account to reservedUnitType and units while the jobs run.
+// After the jobs finish, it waits for the jobs which were active in the account at that time, polling every interval,
+// and then restores the previous type and number of units, even if waiting failed or ctx was canceled.
+// The units are never lowered: if the account already has as many, only the type is changed.
+// The units are restored only if they are still what this call set, so a change made meanwhile is kept.
+// Calls for the same account must not overlap, since each of them restores what it saw before scaling.
+func ScaleForJobs(ctx context.Context, client *ams.Client, reservedUnitType ams.ReservedUnitType, units int, jobIDs []string, interval time.Duration, opts ...option) (jobs []ams.Job, err error) {
+	if ctx == nil {
+		return nil, errors.New("missing ctx")
+	}
+	if client == nil {
+		return nil, errors.New("missing client")
+	}
+	if len(jobIDs) == 0 {
+		return nil, errors.New("missing jobIDs")
+	}
+	if units <= 0 {
+		return nil, errors.New("units must be greater than 0")
+	}
+	if interval <= 0 {
+		return nil, errors.New("interval must be greater than 0")
+	}
+
+	current, err := client.GetEncodingReservedUnitType(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get encoding reserved unit type")
+	}
+	if units > current.MaxReservableUnits {
+		return nil, errors.Errorf("units must not be greater than %d, but got %d", current.MaxReservableUnits, units)
+	}
+	if units < current.CurrentReservedUnits {
+		units = current.CurrentReservedUnits
+	}
+	if reservedUnitType != current.ReservedUnitType || units != current.CurrentReservedUnits {
+		if err := client.UpdateEncodingReservedUnitType(ctx, current.AccountID, reservedUnitType, units); err != nil {
+			return nil, errors.Wrap(err, "failed to scale up encoding reserved units")
+		}
+		defer func() {
+			restoreCtx, cancel := context.WithTimeout(context.Background(), restoreReservedUnitsTimeout)
+			defer cancel()
+			if e := restoreReservedUnits(restoreCtx, client, current, reservedUnitType, units); e != nil && err == nil {
+				err = e
+			}
+		}()
+	}
+
+	jobs, err = WaitJobs(ctx, client, jobIDs, interval, opts...)
+	if err != nil {
+		return jobs, err
+	}
+	if err = waitQueueDrained(ctx, client, interval); err != nil {
+		return jobs, err
+	}
+	return jobs, nil
+}
+
+// restoreReservedUnits sets the units back to previous unless they were changed from reservedUnitType and units meanwhile.
+func restoreReservedUnits(ctx context.Context, client *ams.Client, previous *ams.EncodingReservedUnitType, reservedUnitType ams.ReservedUnitType, units int) error {
+	current, err := client.GetEncodingReservedUnitType(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get encoding reserved unit type")
+	}
+	if current.ReservedUnitType != reservedUnitType || current.CurrentReservedUnits != units {
+		return nil
+	}
+	if err := client.UpdateEncodingReservedUnitType(ctx, previous.AccountID, previous.ReservedUnitType, previous.CurrentReservedUnits); err != nil {
+		return errors.Wrap(err, "failed to scale down encoding reserved units")
+	}
+	return nil
+}
+
+// waitQueueDrained waits until the jobs which are queued, scheduled or processing now have left those states.
+// The jobs submitted later aren't waited for, so it returns even if the account is never idle.
+func waitQueueDrained(ctx context.Context, client *ams.Client, interval time.Duration) error {
+	var pending map[string]bool
+	for {
+		jobs, err := client.GetJobs(ctx, ams.SetJobStates(ams.JobQueued, ams.JobScheduled, ams.JobProcessing))
+		if err != nil {
+			return errors.Wrap(err, "failed to get active jobs")
+		}
+		active := make(map[string]bool)
+		for _, job := range jobs {
+			if pending == nil || pending[job.ID] {
+				active[job.ID] = true
+			}
+		}
+		if len(active) == 0 {
+			return nil
+		}
+		pending = active
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
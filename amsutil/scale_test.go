@@ -0,0 +1,106 @@
+package amsutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recruit-tech/go-ams"
+)
+
+func TestScaleForJobs(t *testing.T) {
+	accountID := "c4b8f3a2-0000-4000-8000-000000000000"
+
+	var (
+		mu      sync.Mutex
+		updates []map[string]interface{}
+		drains  int
+		// changed simulates a change of the units by someone else while the jobs run
+		changed bool
+	)
+	state := ams.EncodingReservedUnitType{AccountID: accountID, ReservedUnitType: ams.ReservedUnitTypeBasic, MaxReservableUnits: 10, CurrentReservedUnits: 1}
+	m := http.NewServeMux()
+	m.HandleFunc("/EncodingReservedUnitTypes", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": []ams.EncodingReservedUnitType{state}})
+	})
+	m.HandleFunc("/EncodingReservedUnitTypes(guid'"+accountID+"')", func(w http.ResponseWriter, r *http.Request) {
+		var params map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		updates = append(updates, params)
+		state.ReservedUnitType = ams.ReservedUnitType(params["ReservedUnitType"].(float64))
+		state.CurrentReservedUnits = int(params["CurrentReservedUnits"].(float64))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	m.HandleFunc("/Jobs", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var jobs []ams.Job
+		if filter := r.URL.Query().Get("$filter"); strings.Contains(filter, "Id eq") {
+			jobs = append(jobs, ams.Job{ID: "job", State: ams.JobFinished})
+			if changed {
+				state.CurrentReservedUnits = 3
+			}
+		} else {
+			// another job is still processing on the first poll, and a job submitted later is never waited for
+			drains++
+			if drains == 1 {
+				jobs = append(jobs, ams.Job{ID: "other", State: ams.JobProcessing})
+			}
+			jobs = append(jobs, ams.Job{ID: fmt.Sprintf("later%d", drains), State: ams.JobQueued})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": jobs})
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := ScaleForJobs(context.TODO(), client, ams.ReservedUnitTypePremium, 5, []string{"job"}, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].State != ams.JobFinished {
+		t.Errorf("unexpected jobs: %#v", jobs)
+	}
+	if drains != 2 {
+		t.Errorf("unexpected drain polls. expected: 2, actual: %d", drains)
+	}
+	expected := []map[string]interface{}{
+		{"ReservedUnitType": float64(ams.ReservedUnitTypePremium), "CurrentReservedUnits": float64(5)},
+		{"ReservedUnitType": float64(ams.ReservedUnitTypeBasic), "CurrentReservedUnits": float64(1)},
+	}
+	if !reflect.DeepEqual(updates, expected) {
+		t.Errorf("unexpected updates. expected: %v, actual: %v", expected, updates)
+	}
+
+	// units changed meanwhile are kept
+	changed = true
+	updates = nil
+	if _, err := ScaleForJobs(context.TODO(), client, ams.ReservedUnitTypePremium, 5, []string{"job"}, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 || state.CurrentReservedUnits != 3 {
+		t.Errorf("units changed meanwhile must be kept. updates: %v, state: %#v", updates, state)
+	}
+
+	if _, err := ScaleForJobs(context.TODO(), client, ams.ReservedUnitTypePremium, 11, []string{"job"}, time.Millisecond); err == nil {
+		t.Error("expected error for units over MaxReservableUnits")
+	}
+}
@@ -0,0 +1,98 @@
+package amsutil
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/recruit-tech/go-ams"
+)
+
+// StorageSelector chooses the storage account in which a new asset is created.
+type StorageSelector interface {
+	SelectStorageAccount(ctx context.Context, client *ams.Client) (string, error)
+}
+
+type StorageSelectorFunc func(ctx context.Context, client *ams.Client) (string, error)
+
+func (f StorageSelectorFunc) SelectStorageAccount(ctx context.Context, client *ams.Client) (string, error) {
+	return f(ctx, client)
+}
+
+func ExplicitStorageSelector(storageAccountName string) StorageSelector {
+	return StorageSelectorFunc(func(ctx context.Context, client *ams.Client) (string, error) {
+		return storageAccountName, nil
+	})
+}
+
+type roundRobinStorageSelector struct {
+	names []string
+
+	m    sync.Mutex
+	next int
+}
+
+// NewRoundRobinStorageSelector returns a StorageSelector which rotates through names.
+// If names is empty, it rotates through all storage accounts attached to the account.
+func NewRoundRobinStorageSelector(names ...string) StorageSelector {
+	return &roundRobinStorageSelector{
+		names: names,
+	}
+}
+
+func (s *roundRobinStorageSelector) SelectStorageAccount(ctx context.Context, client *ams.Client) (string, error) {
+	names := s.names
+	if len(names) == 0 {
+		storageAccounts, err := client.GetStorageAccounts(ctx)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get storage accounts")
+		}
+		for _, storageAccount := range storageAccounts {
+			names = append(names, storageAccount.Name)
+		}
+		sort.Strings(names)
+	}
+	if len(names) == 0 {
+		return "", errors.New("storage accounts not found")
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+	name := names[s.next%len(names)]
+	s.next++
+	return name, nil
+}
+
+// LeastUsedStorageSelector returns a StorageSelector which chooses the storage account with the fewest BytesUsed.
+// Storage accounts which don't expose BytesUsed are ignored, and the default storage account is chosen if none do.
+func LeastUsedStorageSelector() StorageSelector {
+	return StorageSelectorFunc(func(ctx context.Context, client *ams.Client) (string, error) {
+		storageAccounts, err := client.GetStorageAccounts(ctx)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get storage accounts")
+		}
+
+		var selected *ams.StorageAccount
+		for i, storageAccount := range storageAccounts {
+			if storageAccount.BytesUsed == nil {
+				continue
+			}
+			if selected == nil || *storageAccount.BytesUsed < *selected.BytesUsed {
+				selected = &storageAccounts[i]
+			}
+		}
+		if selected == nil {
+			for i, storageAccount := range storageAccounts {
+				if storageAccount.IsDefault {
+					selected = &storageAccounts[i]
+					break
+				}
+			}
+		}
+		if selected == nil {
+			return "", errors.New("storage accounts not found")
+		}
+		return selected.Name, nil
+	})
+}
@@ -0,0 +1,90 @@
+package amsutil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/recruit-tech/go-ams"
+)
+
+func testStorageAccountsClient(t *testing.T, rawStorageAccounts string) (*ams.Client, func()) {
+	m := http.NewServeMux()
+	m.HandleFunc("/StorageAccounts", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, rawStorageAccounts)
+	})
+	s := httptest.NewServer(m)
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		s.Close()
+		t.Fatal(err)
+	}
+	return client, s.Close
+}
+
+func TestRoundRobinStorageSelector(t *testing.T) {
+	client, cleanup := testStorageAccountsClient(t, `{"value":[{"Name":"storage2"},{"Name":"storage1","IsDefault":true}]}`)
+	defer cleanup()
+
+	t.Run("withNames", func(t *testing.T) {
+		selector := NewRoundRobinStorageSelector("a", "b")
+		for _, expected := range []string{"a", "b", "a"} {
+			actual, err := selector.SelectStorageAccount(context.TODO(), client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != expected {
+				t.Errorf("unexpected storage account. expected: %v, actual: %v", expected, actual)
+			}
+		}
+	})
+	t.Run("withoutNames", func(t *testing.T) {
+		selector := NewRoundRobinStorageSelector()
+		for _, expected := range []string{"storage1", "storage2", "storage1"} {
+			actual, err := selector.SelectStorageAccount(context.TODO(), client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != expected {
+				t.Errorf("unexpected storage account. expected: %v, actual: %v", expected, actual)
+			}
+		}
+	})
+}
+
+func TestLeastUsedStorageSelector(t *testing.T) {
+	tcs := []struct {
+		Name               string
+		RawStorageAccounts string
+		Expected           string
+	}{
+		{
+			Name:               "withBytesUsed",
+			RawStorageAccounts: `{"value":[{"Name":"storage1","IsDefault":true,"BytesUsed":"300"},{"Name":"storage2","BytesUsed":"100"},{"Name":"storage3","BytesUsed":null}]}`,
+			Expected:           "storage2",
+		},
+		{
+			Name:               "withoutBytesUsed",
+			RawStorageAccounts: `{"value":[{"Name":"storage1"},{"Name":"storage2","IsDefault":true}]}`,
+			Expected:           "storage2",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.Name, func(t *testing.T) {
+			client, cleanup := testStorageAccountsClient(t, tc.RawStorageAccounts)
+			defer cleanup()
+
+			actual, err := LeastUsedStorageSelector().SelectStorageAccount(context.TODO(), client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tc.Expected {
+				t.Errorf("unexpected storage account. expected: %v, actual: %v", tc.Expected, actual)
+			}
+		})
+	}
+}
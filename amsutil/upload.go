@@ -2,12 +2,17 @@ package amsutil
 
 import (
 	"context"
-	"fmt"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
 	"io"
+	"io/ioutil"
 	"mime"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -59,7 +64,7 @@ func NewUploadableFile(file *os.File) (Uploadable, error) {
 	}, nil
 }
 
-func UploadFile(ctx context.Context, client *ams.Client, file *os.File, chunkSize int64, workers uint) (*ams.Asset, error) {
+func UploadFile(ctx context.Context, client *ams.Client, file *os.File, chunkSize int64, workers uint, opts ...option) (*ams.Asset, error) {
 	if ctx == nil {
 		return nil, errors.New("missing ctx")
 	}
@@ -82,10 +87,10 @@ func UploadFile(ctx context.Context, client *ams.Client, file *os.File, chunkSiz
 		return nil, errors.Errorf("invalid file type. expected video/*, but got '%v'", mimeType)
 	}
 
-	return Upload(ctx, client, u, mimeType, chunkSize, workers)
+	return Upload(ctx, client, u, mimeType, chunkSize, workers, opts...)
 }
 
-func Upload(ctx context.Context, client *ams.Client, uploadable Uploadable, mimeType string, chunkSize int64, workers uint) (*ams.Asset, error) {
+func Upload(ctx context.Context, client *ams.Client, uploadable Uploadable, mimeType string, chunkSize int64, workers uint, opts ...option) (*ams.Asset, error) {
 	if ctx == nil {
 		return nil, errors.New("missing ctx")
 	}
@@ -105,22 +110,117 @@ func Upload(ctx context.Context, client *ams.Client, uploadable Uploadable, mime
 		return nil, errors.New("workers must be greater than 0")
 	}
 
-	name := uploadable.Name()
-	asset, err := client.CreateAsset(ctx, name)
+	options := newOptions(opts)
+
+	files := []uploadFile{
+		{uploadable: uploadable, mimeType: mimeType},
+	}
+	return uploadAsset(ctx, client, uploadable.Name(), files, "", chunkSize, workers, options)
+}
+
+func UploadFiles(ctx context.Context, client *ams.Client, name string, uploadables []Uploadable, primary string, chunkSize int64, workers uint, opts ...option) (*ams.Asset, error) {
+	if ctx == nil {
+		return nil, errors.New("missing ctx")
+	}
+	if client == nil {
+		return nil, errors.New("missing client")
+	}
+	if len(name) == 0 {
+		return nil, errors.New("missing name")
+	}
+	if len(uploadables) == 0 {
+		return nil, errors.New("missing uploadables")
+	}
+	if chunkSize <= 0 {
+		return nil, errors.New("chunkSize must be greater than 0")
+	}
+	if workers == 0 {
+		return nil, errors.New("workers must be greater than 0")
+	}
+
+	files := make([]uploadFile, 0, len(uploadables))
+	names := make(map[string]bool)
+	for _, u := range uploadables {
+		if u == nil {
+			return nil, errors.New("missing uploadable")
+		}
+		if names[u.Name()] {
+			return nil, errors.Errorf("duplicate file name '%v'", u.Name())
+		}
+		names[u.Name()] = true
+		files = append(files, uploadFile{
+			uploadable: u,
+			mimeType:   mimeTypeByName(u.Name()),
+		})
+	}
+	if len(primary) != 0 && !names[primary] {
+		return nil, errors.Errorf("primary file '%v' not found", primary)
+	}
+
+	options := newOptions(opts)
+
+	return uploadAsset(ctx, client, name, files, primary, chunkSize, workers, options)
+}
+
+func UploadDirectory(ctx context.Context, client *ams.Client, dirname, primary string, chunkSize int64, workers uint, opts ...option) (*ams.Asset, error) {
+	if len(dirname) == 0 {
+		return nil, errors.New("missing dirname")
+	}
+
+	fileInfos, err := ioutil.ReadDir(dirname)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to create asset. name='%s'", name)
+		return nil, errors.Wrapf(err, "failed to read directory. dirname='%v'", dirname)
 	}
 
-	assetFile, err := client.CreateAssetFile(ctx, asset.ID, name, mimeType)
+	var uploadables []Uploadable
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.Mode().IsRegular() {
+			continue
+		}
+		file, err := os.Open(filepath.Join(dirname, fileInfo.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open file. name='%v'", fileInfo.Name())
+		}
+		defer file.Close()
+
+		u, _ := NewUploadableFile(file)
+		uploadables = append(uploadables, u)
+	}
+	if len(uploadables) == 0 {
+		return nil, errors.Errorf("files not found. dirname='%v'", dirname)
+	}
+
+	return UploadFiles(ctx, client, filepath.Base(dirname), uploadables, primary, chunkSize, workers, opts...)
+}
+
+type uploadFile struct {
+	uploadable Uploadable
+	mimeType   string
+}
+
+func uploadAsset(ctx context.Context, client *ams.Client, name string, files []uploadFile, primary string, chunkSize int64, workers uint, options *options) (*ams.Asset, error) {
+	if len(options.AssetName) != 0 {
+		name = options.AssetName
+	}
+	asset, err := createAsset(ctx, client, name, options.StorageSelector)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to create asset file. assetID='%s'", asset.ID)
+		return nil, err
+	}
+
+	assetFiles := make([]*ams.AssetFile, len(files))
+	for i, file := range files {
+		assetFile, err := client.CreateAssetFile(ctx, asset.ID, file.uploadable.Name(), file.mimeType)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create asset file. assetID='%s'", asset.ID)
+		}
+		assetFiles[i] = assetFile
 	}
 
-	accessPolicy, err := client.CreateAccessPolicy(ctx, uploadPolicyName, uploadDurationInMinute, ams.PermissionWrite)
+	accessPolicy, release, err := acquireAccessPolicy(ctx, client, options.AccessPolicyPool, uploadPolicyName, uploadDurationInMinute, ams.PermissionWrite)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create access policy")
+		return nil, err
 	}
-	defer client.DeleteAccessPolicy(ctx, accessPolicy.ID)
+	defer release()
 
 	// ref: https://docs.microsoft.com/en-US/azure/media-services/media-services-rest-upload-files
 	// for clock skew
@@ -131,25 +231,129 @@ func Upload(ctx context.Context, client *ams.Client, uploadable Uploadable, mime
 	}
 	defer client.DeleteLocator(ctx, locator.ID)
 
-	uploadURL, err := locator.ToUploadURL(name)
+	fileWorkers, chunkWorkers := splitWorkers(workers, len(files))
+	sha256sums := make([]string, len(files))
+	errs := make([]error, len(files))
+	sem := make(chan struct{}, fileWorkers)
+	wg := new(sync.WaitGroup)
+	for i := range files {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			sha256sums[i], errs[i] = uploadAssetFile(ctx, client, locator, files[i].uploadable, assetFiles[i], chunkSize, chunkWorkers, options.SHA256 != nil)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to upload file. name='%s'", assetFiles[i].Name)
+		}
+	}
+
+	for i, assetFile := range assetFiles {
+		assetFile.IsPrimary = len(primary) != 0 && assetFile.Name == primary
+		if err := client.UpdateAssetFile(ctx, assetFile); err != nil {
+			return nil, errors.Wrap(err, "failed to update asset file")
+		}
+		if options.SHA256 != nil {
+			options.SHA256(assetFile.Name, sha256sums[i])
+		}
+	}
+
+	return asset, nil
+}
+
+// splitWorkers splits the budget of parallel requests between files and the chunks of each file,
+// so that at most workers PUTs run at once.
+func splitWorkers(workers uint, numFiles int) (fileWorkers, chunkWorkers uint) {
+	fileWorkers = workers
+	if uint(numFiles) < fileWorkers {
+		fileWorkers = uint(numFiles)
+	}
+	if fileWorkers == 0 {
+		fileWorkers = 1
+	}
+	chunkWorkers = workers / fileWorkers
+	if chunkWorkers == 0 {
+		chunkWorkers = 1
+	}
+	return fileWorkers, chunkWorkers
+}
+
+func createAsset(ctx context.Context, client *ams.Client, name string, selector StorageSelector) (*ams.Asset, error) {
+	if selector == nil {
+		asset, err := client.CreateAsset(ctx, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create asset. name='%s'", name)
+		}
+		return asset, nil
+	}
+
+	storageAccountName, err := selector.SelectStorageAccount(ctx, client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select storage account")
+	}
+	asset, err := client.CreateAssetWithStorageAccount(ctx, name, storageAccountName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create asset. name='%s', storageAccountName='%s'", name, storageAccountName)
+	}
+	return asset, nil
+}
+
+func uploadAssetFile(ctx context.Context, client *ams.Client, locator *ams.Locator, uploadable Uploadable, assetFile *ams.AssetFile, chunkSize int64, workers uint, withSHA256 bool) (string, error) {
+	uploadURL, err := locator.ToUploadURL(assetFile.Name)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to construct upload url")
+		return "", errors.Wrap(err, "failed to construct upload url")
 	}
 
 	sasc, err := client.NewSASClient(uploadURL.String())
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to construct SASClient")
+		return "", errors.Wrap(err, "failed to construct SASClient")
 	}
 
-	contentLength, err := sasc.Upload(ctx, uploadable, chunkSize, workers)
+	md5Hash := md5.New()
+	hashes := []io.Writer{md5Hash}
+	var sha256Hash hash.Hash
+	if withSHA256 {
+		sha256Hash = sha256.New()
+		hashes = append(hashes, sha256Hash)
+	}
+
+	contentLength, err := sasc.Upload(ctx, io.TeeReader(uploadable, io.MultiWriter(hashes...)), chunkSize, workers)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	assetFile.ContentFileSize = fmt.Sprint(contentLength)
-	if err := client.UpdateAssetFile(ctx, assetFile); err != nil {
-		return nil, errors.Wrap(err, "failed to update asset file")
+	assetFile.ContentFileSize = contentLength
+	assetFile.ContentChecksum = hex.EncodeToString(md5Hash.Sum(nil))
+	if sha256Hash == nil {
+		return "", nil
 	}
+	return hex.EncodeToString(sha256Hash.Sum(nil)), nil
+}
 
-	return asset, nil
+var mimeTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".m4a":  "audio/mp4",
+	".mp3":  "audio/mpeg",
+	".vtt":  "text/vtt",
+	".ttml": "application/ttml+xml",
+	".ism":  "application/octet-stream",
+	".ismc": "application/octet-stream",
+}
+
+func mimeTypeByName(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if mimeType, ok := mimeTypes[ext]; ok {
+		return mimeType
+	}
+	if mimeType := mime.TypeByExtension(ext); len(mimeType) != 0 {
+		if mediaType, _, err := mime.ParseMediaType(mimeType); err == nil {
+			return mediaType
+		}
+	}
+	return "application/octet-stream"
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -20,15 +21,110 @@ func TestUploadFile(t *testing.T) {
 	}
 	defer testFile.Close()
 
-	asset, err := UploadFile(ctx, AMS, testFile, 4*1024*1024, 5)
+	var sha256sum string
+	asset, err := UploadFile(ctx, AMS, testFile, 4*1024*1024, 5, WithSHA256Checksum(func(name, sum string) {
+		sha256sum = sum
+	}))
 	if err != nil {
 		t.Errorf("file uploading failed: %v", err)
 	}
 	if asset == nil {
 		t.Fatal("return invalid asset")
 	}
+	if len(sha256sum) != 64 {
+		t.Errorf("unexpected sha256 checksum: %#v", sha256sum)
+	}
+
+	assetFiles, err := AMS.GetAssetFiles(ctx, asset.ID)
+	if err != nil {
+		t.Errorf("get asset files failed: %v", err)
+	}
+	for _, assetFile := range assetFiles {
+		if assetFile.ContentFileSize == 0 {
+			t.Errorf("missing ContentFileSize. assetFile: %#v", assetFile)
+		}
+		if len(assetFile.ContentChecksum) == 0 {
+			t.Errorf("missing ContentChecksum. assetFile: %#v", assetFile)
+		}
+	}
 
 	if err := AMS.DeleteAsset(ctx, asset.ID); err != nil {
 		t.Errorf("asset delete failed: %v", err)
 	}
 }
+
+func TestUploadFiles(t *testing.T) {
+	ctx := context.TODO()
+	cnf := testConfigFromFile(t, "config.json")
+	AMS, err := cnf.Client(ctx)
+	if err != nil {
+		t.Fatalf("client build failed")
+	}
+	testFile, err := os.Open(filepath.Join(cnf.BaseDir, "testdata", "small.mp4"))
+	if err != nil {
+		t.Fatalf("video file open failed: %v", err)
+	}
+	defer testFile.Close()
+
+	video, _ := NewUploadableFile(testFile)
+	caption, _ := NewUploadable("small.vtt", strings.NewReader("WEBVTT\n\n00:00.000 --> 00:01.000\nhello\n"))
+
+	asset, err := UploadFiles(ctx, AMS, "small", []Uploadable{video, caption}, video.Name(), 4*1024*1024, 5)
+	if err != nil {
+		t.Fatalf("files uploading failed: %v", err)
+	}
+
+	assetFiles, err := AMS.GetAssetFiles(ctx, asset.ID)
+	if err != nil {
+		t.Errorf("get asset files failed: %v", err)
+	}
+	if len(assetFiles) != 2 {
+		t.Errorf("unexpected asset files. expected: 2 files, actual: %#v", assetFiles)
+	}
+	for _, assetFile := range assetFiles {
+		if expected := assetFile.Name == video.Name(); assetFile.IsPrimary != expected {
+			t.Errorf("unexpected IsPrimary. expected: %v, actual: %#v", expected, assetFile)
+		}
+	}
+
+	if err := AMS.DeleteAsset(ctx, asset.ID); err != nil {
+		t.Errorf("asset delete failed: %v", err)
+	}
+}
+
+func TestSplitWorkers(t *testing.T) {
+	cases := []struct {
+		workers  uint
+		numFiles int
+		files    uint
+		chunks   uint
+	}{
+		{workers: 8, numFiles: 2, files: 2, chunks: 4},
+		{workers: 8, numFiles: 3, files: 3, chunks: 2},
+		{workers: 2, numFiles: 5, files: 2, chunks: 1},
+		{workers: 4, numFiles: 1, files: 1, chunks: 4},
+	}
+	for _, c := range cases {
+		files, chunks := splitWorkers(c.workers, c.numFiles)
+		if files != c.files || chunks != c.chunks {
+			t.Errorf("splitWorkers(%d, %d): expected (%d, %d), actual (%d, %d)", c.workers, c.numFiles, c.files, c.chunks, files, chunks)
+		}
+		if files*chunks > c.workers {
+			t.Errorf("splitWorkers(%d, %d): %d parallel requests exceed workers", c.workers, c.numFiles, files*chunks)
+		}
+	}
+}
+
+func TestMimeTypeByName(t *testing.T) {
+	cases := map[string]string{
+		"video.MP4":    "video/mp4",
+		"captions.vtt": "text/vtt",
+		"index.html":   "text/html",
+		"unknown.xyz0": "application/octet-stream",
+	}
+	for name, expected := range cases {
+		if actual := mimeTypeByName(name); actual != expected {
+			t.Errorf("mimeTypeByName(%q): expected %v, actual %v", name, expected, actual)
+		}
+	}
+}
@@ -2,37 +2,212 @@ package amsutil
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/recruit-tech/go-ams"
 )
 
-func WaitJob(ctx context.Context, client *ams.Client, jobID string, duration time.Duration) error {
-	if ctx == nil {
-		return errors.New("missing ctx")
+const (
+	defaultMaxPollInterval = time.Minute
+	pollBackoffFactor      = 1.5
+
+	// waitJobsChunkSize bounds the number of "Id eq" conditions in a single $filter.
+	waitJobsChunkSize = 50
+)
+
+// JobFailedError is returned when a job ends in ams.JobError or ams.JobCanceled.
+// Task is the failing task, if any, and ErrorDetails are its error details.
+type JobFailedError struct {
+	Job          ams.Job
+	Task         *ams.Task
+	ErrorDetails []ams.ErrorDetail
+}
+
+func (e *JobFailedError) Error() string {
+	if e.Job.State == ams.JobCanceled {
+		return fmt.Sprintf("job canceled. jobID='%v'", e.Job.ID)
 	}
-	if client == nil {
-		return errors.New("missing client")
+	msg := fmt.Sprintf("job failed. jobID='%v'", e.Job.ID)
+	if e.Task != nil {
+		msg += fmt.Sprintf(", task='%v'", e.Task.Name)
 	}
+	for _, detail := range e.ErrorDetails {
+		msg += fmt.Sprintf(", %v: %v", detail.Code, detail.Message)
+	}
+	return msg
+}
+
+// WaitJob waits until the job finishes, polling every interval at first and backing off while nothing changes.
+// It returns a *JobFailedError if the job ends in error or is canceled.
+func WaitJob(ctx context.Context, client *ams.Client, jobID string, interval time.Duration, opts ...option) error {
 	if len(jobID) == 0 {
 		return errors.New("missing jobID")
 	}
+	_, err := WaitJobs(ctx, client, []string{jobID}, interval, opts...)
+	return err
+}
+
+// WaitJobs waits until all the jobs reach a final state, querying their states with a GetJobs per 50 pending jobs per tick.
+// It returns the final jobs in the order of jobIDs. If some of them failed, the error is the *JobFailedError of the first one.
+// It returns an error if a job is not found.
+func WaitJobs(ctx context.Context, client *ams.Client, jobIDs []string, interval time.Duration, opts ...option) ([]ams.Job, error) {
+	if ctx == nil {
+		return nil, errors.New("missing ctx")
+	}
+	if client == nil {
+		return nil, errors.New("missing client")
+	}
+	if len(jobIDs) == 0 {
+		return nil, errors.New("missing jobIDs")
+	}
+	if interval <= 0 {
+		return nil, errors.New("interval must be greater than 0")
+	}
+
+	options := newOptions(opts)
+	maxInterval := options.MaxPollInterval
+	if maxInterval == 0 {
+		maxInterval = defaultMaxPollInterval
+		if maxInterval < interval {
+			maxInterval = interval
+		}
+	}
+	if maxInterval < interval {
+		return nil, errors.Errorf("max poll interval %v must not be less than interval %v", maxInterval, interval)
+	}
+
+	// jobIDs may have duplicates, which are polled once and returned at each of their positions
+	var uniqueIDs []string
+	seen := make(map[string]bool)
+	for _, jobID := range jobIDs {
+		if !seen[jobID] {
+			seen[jobID] = true
+			uniqueIDs = append(uniqueIDs, jobID)
+		}
+	}
+
+	finished := make(map[string]ams.Job)
+	states := make(map[string]int)
+	progresses := make(map[string]float64)
+	wait := interval
 	for {
-		current, err := client.GetJob(ctx, jobID)
+		var pending []string
+		for _, jobID := range uniqueIDs {
+			if _, ok := finished[jobID]; !ok {
+				pending = append(pending, jobID)
+			}
+		}
+		jobs, err := getJobsByIDs(ctx, client, pending)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		if current.State == ams.JobError {
-			return errors.New("job failed")
+		changed := false
+		for _, job := range jobs {
+			if state, ok := states[job.ID]; !ok || state != job.State {
+				states[job.ID] = job.State
+				changed = true
+			}
+			if isFinalJobState(job.State) {
+				finished[job.ID] = job
+				continue
+			}
+			if options.Progress != nil && job.State == ams.JobProcessing {
+				tasks, err := client.GetJobTasks(ctx, job.ID)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to get job tasks. jobID='%v'", job.ID)
+				}
+				progress := 0.0
+				for _, task := range tasks {
+					progress += task.Progress
+				}
+				if progress != progresses[job.ID] {
+					progresses[job.ID] = progress
+					changed = true
+				}
+				options.Progress(job, tasks)
+			}
 		}
-		if current.State == ams.JobCanceled {
-			return errors.New("job canceled")
+		if len(finished) == len(uniqueIDs) {
+			break
+		}
+
+		if changed {
+			wait = interval
+		} else {
+			wait = time.Duration(float64(wait) * pollBackoffFactor)
+			if wait > maxInterval {
+				wait = maxInterval
+			}
 		}
-		if current.State == ams.JobFinished {
-			return nil
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+
+	results := make([]ams.Job, 0, len(jobIDs))
+	var firstErr error
+	for _, jobID := range jobIDs {
+		job := finished[jobID]
+		results = append(results, job)
+		if firstErr == nil && job.State != ams.JobFinished {
+			firstErr = newJobFailedError(ctx, client, job)
+		}
+	}
+	return results, firstErr
+}
+
+// getJobsByIDs gets the jobs in chunks of waitJobsChunkSize, and fails if one of them is not found.
+func getJobsByIDs(ctx context.Context, client *ams.Client, jobIDs []string) ([]ams.Job, error) {
+	var jobs []ams.Job
+	for start := 0; start < len(jobIDs); start += waitJobsChunkSize {
+		end := start + waitJobsChunkSize
+		if end > len(jobIDs) {
+			end = len(jobIDs)
+		}
+		chunk, err := client.GetJobs(ctx, ams.SetJobIDs(jobIDs[start:end]...))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get jobs")
 		}
-		time.Sleep(duration)
+		jobs = append(jobs, chunk...)
 	}
+
+	found := make(map[string]bool)
+	for _, job := range jobs {
+		found[job.ID] = true
+	}
+	for _, jobID := range jobIDs {
+		if !found[jobID] {
+			return nil, errors.Errorf("job not found. jobID='%v'", jobID)
+		}
+	}
+	return jobs, nil
+}
+
+func newJobFailedError(ctx context.Context, client *ams.Client, job ams.Job) error {
+	jobErr := &JobFailedError{Job: job}
+	if job.State != ams.JobError {
+		return jobErr
+	}
+	tasks, err := client.GetJobTasks(ctx, job.ID)
+	if err != nil {
+		return errors.Wrapf(err, "job failed, and failed to get job tasks. jobID='%v'", job.ID)
+	}
+	for _, task := range tasks {
+		if len(task.ErrorDetails) != 0 {
+			task := task
+			jobErr.Task = &task
+			jobErr.ErrorDetails = task.ErrorDetails
+			break
+		}
+	}
+	return jobErr
+}
+
+func isFinalJobState(state int) bool {
+	return state == ams.JobFinished || state == ams.JobError || state == ams.JobCanceled
 }
@@ -0,0 +1,236 @@
+package amsutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recruit-tech/go-ams"
+)
+
+func TestWaitJobs(t *testing.T) {
+	// job1: Processing -> Finished, job2: Processing -> Error
+	var mu sync.Mutex
+	polls := 0
+	m := http.NewServeMux()
+	m.HandleFunc("/Jobs", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		polls++
+
+		filter := r.URL.Query().Get("$filter")
+		var jobs []ams.Job
+		if strings.Contains(filter, "Id eq 'job1'") {
+			state := ams.JobProcessing
+			if polls >= 2 {
+				state = ams.JobFinished
+			}
+			jobs = append(jobs, ams.Job{ID: "job1", State: state})
+		}
+		if strings.Contains(filter, "Id eq 'job2'") {
+			state := ams.JobProcessing
+			if polls >= 3 {
+				state = ams.JobError
+			}
+			jobs = append(jobs, ams.Job{ID: "job2", State: state})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": jobs})
+	})
+	for _, jobID := range []string{"job1", "job2"} {
+		jobID := jobID
+		m.HandleFunc(fmt.Sprintf("/Jobs('%v')/Tasks", jobID), func(w http.ResponseWriter, r *http.Request) {
+			task := ams.Task{ID: jobID + "-task", Name: "Encode", Progress: 50}
+			if jobID == "job2" {
+				task.ErrorDetails = []ams.ErrorDetail{{Code: "ErrorDownloadingInputAssetMalformedContent", Message: "broken input"}}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"value": []ams.Task{task}})
+		})
+	}
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress := make(map[string]int)
+	jobs, err := WaitJobs(context.TODO(), client, []string{"job1", "job2"}, time.Millisecond,
+		WithProgress(func(job ams.Job, tasks []ams.Task) {
+			progress[job.ID]++
+		}),
+	)
+	jobErr, ok := err.(*JobFailedError)
+	if !ok {
+		t.Fatalf("unexpected error. expected: *JobFailedError, actual: %#v", err)
+	}
+	if jobErr.Job.ID != "job2" || jobErr.Task == nil || len(jobErr.ErrorDetails) != 1 {
+		t.Errorf("unexpected JobFailedError: %#v", jobErr)
+	}
+	if !strings.Contains(jobErr.Error(), "broken input") {
+		t.Errorf("error message must contain the error details: %v", jobErr.Error())
+	}
+	if len(jobs) != 2 || jobs[0].State != ams.JobFinished || jobs[1].State != ams.JobError {
+		t.Errorf("unexpected jobs: %#v", jobs)
+	}
+	if polls != 3 {
+		t.Errorf("unexpected polls. expected: 3, actual: %v", polls)
+	}
+	if progress["job1"] != 1 || progress["job2"] != 2 {
+		t.Errorf("unexpected progress calls: %v", progress)
+	}
+}
+
+func TestWaitJob_context(t *testing.T) {
+	m := http.NewServeMux()
+	m.HandleFunc("/Jobs", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": []ams.Job{{ID: "job", State: ams.JobProcessing}}})
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = WaitJob(ctx, client, "job", time.Hour)
+	if err != context.DeadlineExceeded {
+		t.Errorf("unexpected error. expected: %v, actual: %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("WaitJob must return on ctx cancellation, but took %v", elapsed)
+	}
+}
+
+func TestWaitJobs_notFound(t *testing.T) {
+	m := http.NewServeMux()
+	m.HandleFunc("/Jobs", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": []ams.Job{{ID: "job1", State: ams.JobProcessing}}})
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = WaitJobs(ctx, client, []string{"job1", "missing"}, time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := WaitJob(ctx, client, "missing", time.Millisecond); err == nil || err == context.DeadlineExceeded {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWaitJobs_duplicate(t *testing.T) {
+	polls := 0
+	m := http.NewServeMux()
+	m.HandleFunc("/Jobs", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		filter := r.URL.Query().Get("$filter")
+		if strings.Count(filter, "Id eq 'job1'") > 1 {
+			t.Errorf("duplicate job id in filter: %v", filter)
+		}
+		var jobs []ams.Job
+		for _, jobID := range []string{"job1", "job2"} {
+			if strings.Contains(filter, fmt.Sprintf("Id eq '%v'", jobID)) {
+				jobs = append(jobs, ams.Job{ID: jobID, State: ams.JobFinished})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": jobs})
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	jobs, err := WaitJobs(ctx, client, []string{"job1", "job2", "job1"}, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	if expected := []string{"job1", "job2", "job1"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("unexpected jobs. expected: %v, actual: %v", expected, ids)
+	}
+	if polls != 1 {
+		t.Errorf("unexpected polls. expected: 1, actual: %v", polls)
+	}
+}
+
+func TestWaitJobs_chunk(t *testing.T) {
+	var jobIDs []string
+	for i := 0; i < 2*waitJobsChunkSize+1; i++ {
+		jobIDs = append(jobIDs, fmt.Sprintf("job%d", i))
+	}
+
+	requests := 0
+	m := http.NewServeMux()
+	m.HandleFunc("/Jobs", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		filter := r.URL.Query().Get("$filter")
+		var jobs []ams.Job
+		for _, jobID := range jobIDs {
+			if strings.Contains(filter, fmt.Sprintf("Id eq '%v'", jobID)) {
+				jobs = append(jobs, ams.Job{ID: jobID, State: ams.JobFinished})
+			}
+		}
+		if len(jobs) > waitJobsChunkSize {
+			t.Errorf("too many jobs in a request: %v", len(jobs))
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": jobs})
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client, err := ams.NewClient(s.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := WaitJobs(context.TODO(), client, jobIDs, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != len(jobIDs) {
+		t.Errorf("unexpected jobs. expected: %v, actual: %v", len(jobIDs), len(jobs))
+	}
+	if requests != 3 {
+		t.Errorf("unexpected requests. expected: 3, actual: %v", requests)
+	}
+}
+
+func TestWaitJobs_maxPollInterval(t *testing.T) {
+	client, err := ams.NewClient("http://localhost", http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WaitJobs(context.TODO(), client, []string{"job"}, time.Minute, WithMaxPollInterval(time.Second)); err == nil {
+		t.Error("accept max poll interval less than interval")
+	}
+}
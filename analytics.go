@@ -0,0 +1,293 @@
+package ams
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AnalyticsConfiguration is the task configuration of a Media Analytics processor.
+// ref: https://docs.microsoft.com/en-us/azure/media-services/previous/media-services-analytics-overview
+type AnalyticsConfiguration interface {
+	// MediaProcessorName is the name to resolve the processor by (MediaProcessor*).
+	MediaProcessorName() string
+	// Configuration returns the configuration string of the task.
+	Configuration() (string, error)
+}
+
+const (
+	IndexerCaptionFormatTTML   = "ttml"
+	IndexerCaptionFormatSAMI   = "sami"
+	IndexerCaptionFormatWebVTT = "webvtt"
+)
+
+// IndexerConfiguration configures Azure Media Indexer, which generates captions and keywords from speech.
+type IndexerConfiguration struct {
+	Title            string
+	Description      string
+	Language         string
+	CaptionFormats   []string
+	GenerateAIB      bool
+	GenerateKeywords bool
+}
+
+func (c *IndexerConfiguration) MediaProcessorName() string {
+	return MediaProcessorIndexer
+}
+
+type indexerSetting struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:"value,attr"`
+}
+
+func (c *IndexerConfiguration) Configuration() (string, error) {
+	for _, format := range c.CaptionFormats {
+		switch format {
+		case IndexerCaptionFormatTTML, IndexerCaptionFormatSAMI, IndexerCaptionFormatWebVTT:
+		default:
+			return "", errors.Errorf("unknown caption format '%v'", format)
+		}
+	}
+	language := c.Language
+	if len(language) == 0 {
+		language = "English"
+	}
+
+	type feature struct {
+		Name     string           `xml:"name,attr"`
+		Settings []indexerSetting `xml:"settings>add"`
+	}
+	config := struct {
+		XMLName  xml.Name         `xml:"configuration"`
+		Version  string           `xml:"version,attr"`
+		Metadata []indexerSetting `xml:"input>metadata"`
+		Features []feature        `xml:"features>feature"`
+	}{
+		Version: "2.0",
+		Features: []feature{
+			{
+				Name: "ASR",
+				Settings: []indexerSetting{
+					{Key: "Language", Value: language},
+					{Key: "CaptionFormats", Value: strings.Join(c.CaptionFormats, ";")},
+					{Key: "GenerateAIB", Value: fmt.Sprint(c.GenerateAIB)},
+					{Key: "GenerateKeywords", Value: fmt.Sprint(c.GenerateKeywords)},
+				},
+			},
+		},
+	}
+	if len(c.Title) != 0 {
+		config.Metadata = append(config.Metadata, indexerSetting{Key: "title", Value: c.Title})
+	}
+	if len(c.Description) != 0 {
+		config.Metadata = append(config.Metadata, indexerSetting{Key: "description", Value: c.Description})
+	}
+	b, err := xml.Marshal(&config)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to xml.Marshal configuration")
+	}
+	return xml.Header + string(b), nil
+}
+
+const (
+	FaceDetectionModeFaces            = "faces"
+	FaceDetectionModePerFaceEmotion   = "perFaceEmotion"
+	FaceDetectionModeAggregateEmotion = "aggregateEmotion"
+)
+
+// FaceDetectorConfiguration configures Azure Media Face Detector.
+// AggregateEmotionWindow and AggregateEmotionInterval are used in FaceDetectionModeAggregateEmotion only.
+type FaceDetectorConfiguration struct {
+	Mode                     string
+	AggregateEmotionWindow   time.Duration
+	AggregateEmotionInterval time.Duration
+}
+
+func (c *FaceDetectorConfiguration) MediaProcessorName() string {
+	return MediaProcessorFaceDetector
+}
+
+func (c *FaceDetectorConfiguration) Configuration() (string, error) {
+	options := map[string]interface{}{}
+	switch c.Mode {
+	case "", FaceDetectionModeFaces, FaceDetectionModePerFaceEmotion:
+	case FaceDetectionModeAggregateEmotion:
+		if c.AggregateEmotionWindow > 0 {
+			options["aggregateEmotionWindowMs"] = fmt.Sprint(int64(c.AggregateEmotionWindow / time.Millisecond))
+		}
+		if c.AggregateEmotionInterval > 0 {
+			options["aggregateEmotionIntervalMs"] = fmt.Sprint(int64(c.AggregateEmotionInterval / time.Millisecond))
+		}
+	default:
+		return "", errors.Errorf("unknown mode '%v'", c.Mode)
+	}
+	if len(c.Mode) != 0 {
+		options["mode"] = c.Mode
+	}
+	return marshalAnalyticsConfiguration("1.0", options)
+}
+
+const (
+	MotionSensitivityLow    = "low"
+	MotionSensitivityMedium = "medium"
+	MotionSensitivityHigh   = "high"
+)
+
+// MotionDetectionPoint is relative to the frame size (0.0-1.0).
+type MotionDetectionPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// MotionDetectorConfiguration configures Azure Media Motion Detector.
+type MotionDetectorConfiguration struct {
+	SensitivityLevel   string
+	FrameSamplingValue int
+	DetectLightChange  bool
+	MergeTimeThreshold time.Duration
+	DetectionZones     [][]MotionDetectionPoint
+}
+
+func (c *MotionDetectorConfiguration) MediaProcessorName() string {
+	return MediaProcessorMotionDetector
+}
+
+func (c *MotionDetectorConfiguration) Configuration() (string, error) {
+	options := map[string]interface{}{
+		"detectLightChange": strings.Title(fmt.Sprint(c.DetectLightChange)),
+	}
+	switch c.SensitivityLevel {
+	case "":
+	case MotionSensitivityLow, MotionSensitivityMedium, MotionSensitivityHigh:
+		options["sensitivityLevel"] = c.SensitivityLevel
+	default:
+		return "", errors.Errorf("unknown sensitivity level '%v'", c.SensitivityLevel)
+	}
+	if c.FrameSamplingValue < 0 {
+		return "", errors.New("FrameSamplingValue must not be negative")
+	}
+	if c.FrameSamplingValue > 0 {
+		options["frameSamplingValue"] = c.FrameSamplingValue
+	}
+	if c.MergeTimeThreshold > 0 {
+		options["mergeTimeThreshold"] = formatTimestamp(c.MergeTimeThreshold)
+	}
+	for i, zone := range c.DetectionZones {
+		if len(zone) < 3 {
+			return "", errors.Errorf("DetectionZones[%d] must have at least 3 points", i)
+		}
+	}
+	if len(c.DetectionZones) != 0 {
+		options["detectionZones"] = c.DetectionZones
+	}
+	return marshalAnalyticsConfiguration("1.0", options)
+}
+
+const (
+	OCRTextOrientationAuto  = "Auto"
+	OCRTextOrientationUp    = "Up"
+	OCRTextOrientationRight = "Right"
+	OCRTextOrientationDown  = "Down"
+	OCRTextOrientationLeft  = "Left"
+)
+
+// OCRRegion is in pixels.
+type OCRRegion struct {
+	Left   int `json:"Left,string"`
+	Top    int `json:"Top,string"`
+	Width  int `json:"Width,string"`
+	Height int `json:"Height,string"`
+}
+
+// OCRConfiguration configures Azure Media OCR. An empty Language detects the language automatically.
+type OCRConfiguration struct {
+	Language        string
+	TextOrientation string
+	TimeInterval    time.Duration
+	AdvancedOutput  bool
+	DetectRegions   []OCRRegion
+}
+
+func (c *OCRConfiguration) MediaProcessorName() string {
+	return MediaProcessorOCR
+}
+
+func (c *OCRConfiguration) Configuration() (string, error) {
+	options := map[string]interface{}{
+		"AdvancedOutput": fmt.Sprint(c.AdvancedOutput),
+	}
+	if len(c.Language) != 0 {
+		options["Language"] = c.Language
+	}
+	switch c.TextOrientation {
+	case "":
+	case OCRTextOrientationAuto, OCRTextOrientationUp, OCRTextOrientationRight, OCRTextOrientationDown, OCRTextOrientationLeft:
+		options["TextOrientation"] = c.TextOrientation
+	default:
+		return "", errors.Errorf("unknown text orientation '%v'", c.TextOrientation)
+	}
+	if c.TimeInterval > 0 {
+		options["TimeInterval"] = formatTimestamp(c.TimeInterval)
+	}
+	if len(c.DetectRegions) != 0 {
+		options["DetectRegions"] = c.DetectRegions
+	}
+	return marshalAnalyticsConfiguration("1.0", options)
+}
+
+// VideoSummarizationConfiguration configures Azure Media Video Thumbnails, which creates a summary video.
+type VideoSummarizationConfiguration struct {
+	OutputAudio                bool
+	MaxMotionThumbnailDuration time.Duration
+	FadeInFadeOut              bool
+}
+
+func (c *VideoSummarizationConfiguration) MediaProcessorName() string {
+	return MediaProcessorVideoThumbnails
+}
+
+func (c *VideoSummarizationConfiguration) Configuration() (string, error) {
+	options := map[string]interface{}{
+		"outputAudio":   fmt.Sprint(c.OutputAudio),
+		"fadeInFadeOut": fmt.Sprint(c.FadeInFadeOut),
+	}
+	if c.MaxMotionThumbnailDuration > 0 {
+		options["maxMotionThumbnailDurationInSecs"] = fmt.Sprint(int64(c.MaxMotionThumbnailDuration / time.Second))
+	}
+	return marshalAnalyticsConfiguration("1.0", options)
+}
+
+// ContentModeratorConfiguration configures Azure Media Content Moderator.
+type ContentModeratorConfiguration struct{}
+
+func (c *ContentModeratorConfiguration) MediaProcessorName() string {
+	return MediaProcessorContentModerator
+}
+
+func (c *ContentModeratorConfiguration) Configuration() (string, error) {
+	return `{"version":"2.0"}`, nil
+}
+
+func marshalAnalyticsConfiguration(version string, options map[string]interface{}) (string, error) {
+	b, err := json.Marshal(map[string]interface{}{
+		"version": version,
+		"options": options,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to json.Marshal configuration")
+	}
+	return string(b), nil
+}
+
+// formatTimestamp formats d as "hh:mm:ss.fff".
+func formatTimestamp(d time.Duration) string {
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+	ms := (d % time.Second) / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, ms)
+}
@@ -0,0 +1,271 @@
+package ams
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AnalyticsTimeline is the header of the JSON output of Face Detector, Motion Detector, OCR and Content Moderator.
+// Times in the fragments are in Timescale ticks per second.
+type AnalyticsTimeline struct {
+	Version   int     `json:"version"`
+	Timescale int64   `json:"timescale"`
+	Offset    int64   `json:"offset"`
+	Framerate float64 `json:"framerate"`
+	Width     int     `json:"width"`
+	Height    int     `json:"height"`
+}
+
+// ToDuration converts ticks in Timescale to time.Duration.
+func (t *AnalyticsTimeline) ToDuration(ticks int64) time.Duration {
+	if t.Timescale == 0 {
+		return 0
+	}
+	return time.Duration(ticks/t.Timescale)*time.Second + time.Duration(ticks%t.Timescale)*time.Second/time.Duration(t.Timescale)
+}
+
+// AnalyticsFragment is a period of the timeline. The events of the i-th interval are in Events[i] of each result.
+type AnalyticsFragment struct {
+	Start    int64 `json:"start"`
+	Duration int64 `json:"duration"`
+	Interval int64 `json:"interval"`
+}
+
+// FaceEvent is the location of a face, relative to the frame size (0.0-1.0).
+type FaceEvent struct {
+	ID     int                `json:"id"`
+	X      float64            `json:"x"`
+	Y      float64            `json:"y"`
+	Width  float64            `json:"width"`
+	Height float64            `json:"height"`
+	Scores map[string]float64 `json:"scores,omitempty"`
+}
+
+type FaceDetectionResult struct {
+	AnalyticsTimeline
+	Fragments []FaceDetectionFragment `json:"fragments"`
+}
+
+type FaceDetectionFragment struct {
+	AnalyticsFragment
+	Events [][]FaceEvent `json:"events"`
+}
+
+func ParseFaceDetectionResult(r io.Reader) (*FaceDetectionResult, error) {
+	var result FaceDetectionResult
+	if err := decodeAnalyticsResult(r, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type MotionEvent struct {
+	Type      int                   `json:"type"`
+	TypeName  string                `json:"typeName"`
+	RegionID  int                   `json:"regionId"`
+	Locations []MotionEventLocation `json:"locations"`
+}
+
+type MotionEventLocation struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+type MotionRegion struct {
+	ID     int                    `json:"id"`
+	Type   string                 `json:"type"`
+	Points []MotionDetectionPoint `json:"points"`
+}
+
+type MotionDetectionResult struct {
+	AnalyticsTimeline
+	Regions   []MotionRegion            `json:"regions"`
+	Fragments []MotionDetectionFragment `json:"fragments"`
+}
+
+type MotionDetectionFragment struct {
+	AnalyticsFragment
+	Events [][]MotionEvent `json:"events"`
+}
+
+func ParseMotionDetectionResult(r io.Reader) (*MotionDetectionResult, error) {
+	var result MotionDetectionResult
+	if err := decodeAnalyticsResult(r, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type OCRWord struct {
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+	Left       int     `json:"left"`
+	Top        int     `json:"top"`
+	Right      int     `json:"right"`
+	Bottom     int     `json:"bottom"`
+}
+
+type OCRLine struct {
+	Text   string    `json:"text"`
+	Left   int       `json:"left"`
+	Top    int       `json:"top"`
+	Right  int       `json:"right"`
+	Bottom int       `json:"bottom"`
+	Words  []OCRWord `json:"word"`
+}
+
+type OCREvent struct {
+	Language    string    `json:"language"`
+	TextAngle   float64   `json:"textAngle"`
+	Orientation string    `json:"orientation"`
+	Lines       []OCRLine `json:"lines"`
+}
+
+type OCRResult struct {
+	AnalyticsTimeline
+	Fragments []OCRFragment `json:"fragments"`
+}
+
+type OCRFragment struct {
+	AnalyticsFragment
+	Events [][]OCREvent `json:"events"`
+}
+
+func ParseOCRResult(r io.Reader) (*OCRResult, error) {
+	var result OCRResult
+	if err := decodeAnalyticsResult(r, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ModerationEvent is a frame of a shot, which is rated adult and racy (0.0-1.0).
+type ModerationEvent struct {
+	ReviewRecommended bool    `json:"reviewRecommended"`
+	AdultScore        float64 `json:"adultScore"`
+	RacyScore         float64 `json:"racyScore"`
+	Index             int     `json:"index"`
+	Timestamp         int64   `json:"timestamp"`
+	ShotIndex         int     `json:"shotIndex"`
+}
+
+type ContentModerationResult struct {
+	AnalyticsTimeline
+	Fragments []ContentModerationFragment `json:"fragments"`
+}
+
+type ContentModerationFragment struct {
+	AnalyticsFragment
+	Events [][]ModerationEvent `json:"events"`
+}
+
+func ParseContentModerationResult(r io.Reader) (*ContentModerationResult, error) {
+	var result ContentModerationResult
+	if err := decodeAnalyticsResult(r, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func decodeAnalyticsResult(r io.Reader, v interface{}) error {
+	if r == nil {
+		return errors.New("missing r")
+	}
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		return errors.Wrap(err, "failed to decode analytics result")
+	}
+	return nil
+}
+
+// WebVTTCue is a caption of a WebVTT file, such as the captions generated by Azure Media Indexer.
+type WebVTTCue struct {
+	ID    string
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+var webVTTTimingPattern = regexp.MustCompile(`^((?:\d+:)?\d{2}:\d{2}\.\d{3})\s+-->\s+((?:\d+:)?\d{2}:\d{2}\.\d{3})`)
+
+// ParseWebVTT parses the cues of a WebVTT file.
+func ParseWebVTT(r io.Reader) ([]WebVTTCue, error) {
+	if r == nil {
+		return nil, errors.New("missing r")
+	}
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() || !strings.HasPrefix(strings.TrimPrefix(scanner.Text(), "\ufeff"), "WEBVTT") {
+		return nil, errors.New("missing WEBVTT header")
+	}
+
+	var cues []WebVTTCue
+	var block []string
+	flush := func() error {
+		defer func() { block = block[:0] }()
+		for i, line := range block {
+			m := webVTTTimingPattern.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			start, err := parseWebVTTTimestamp(m[1])
+			if err != nil {
+				return err
+			}
+			end, err := parseWebVTTTimestamp(m[2])
+			if err != nil {
+				return err
+			}
+			cue := WebVTTCue{Start: start, End: end, Text: strings.Join(block[i+1:], "\n")}
+			if i > 0 {
+				cue.ID = block[i-1]
+			}
+			cues = append(cues, cue)
+			return nil
+		}
+		return nil
+	}
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) == 0 {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read WebVTT")
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return cues, nil
+}
+
+// parseWebVTTTimestamp parses "hh:mm:ss.ttt" or "mm:ss.ttt".
+func parseWebVTTTimestamp(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	minutes := 0
+	for _, part := range parts[:len(parts)-1] {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid timestamp '%v'", s)
+		}
+		minutes = minutes*60 + n
+	}
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid timestamp '%v'", s)
+	}
+	return time.Duration(minutes)*time.Minute + time.Duration(math.Round(seconds*1000))*time.Millisecond, nil
+}
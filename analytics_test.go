@@ -0,0 +1,139 @@
+package ams
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyticsConfiguration(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Config   AnalyticsConfiguration
+		Expected string
+	}{
+		{
+			Name:     "faceDetector",
+			Config:   &FaceDetectorConfiguration{Mode: FaceDetectionModeAggregateEmotion, AggregateEmotionWindow: 987 * time.Millisecond, AggregateEmotionInterval: 342 * time.Millisecond},
+			Expected: `{"options":{"aggregateEmotionIntervalMs":"342","aggregateEmotionWindowMs":"987","mode":"aggregateEmotion"},"version":"1.0"}`,
+		},
+		{
+			Name: "motionDetector",
+			Config: &MotionDetectorConfiguration{
+				SensitivityLevel:   MotionSensitivityMedium,
+				FrameSamplingValue: 1,
+				MergeTimeThreshold: 2 * time.Second,
+				DetectionZones:     [][]MotionDetectionPoint{{{X: 0, Y: 0}, {X: 0.5, Y: 0}, {X: 0, Y: 1}}},
+			},
+			Expected: `{"options":{"detectLightChange":"False","detectionZones":[[{"x":0,"y":0},{"x":0.5,"y":0},{"x":0,"y":1}]],"frameSamplingValue":1,"mergeTimeThreshold":"00:00:02.000","sensitivityLevel":"medium"},"version":"1.0"}`,
+		},
+		{
+			Name:     "ocr",
+			Config:   &OCRConfiguration{Language: "English", TextOrientation: OCRTextOrientationUp, TimeInterval: 1500 * time.Millisecond, DetectRegions: []OCRRegion{{Left: 1, Top: 2, Width: 3, Height: 4}}},
+			Expected: `{"options":{"AdvancedOutput":"false","DetectRegions":[{"Left":"1","Top":"2","Width":"3","Height":"4"}],"Language":"English","TextOrientation":"Up","TimeInterval":"00:00:01.500"},"version":"1.0"}`,
+		},
+		{
+			Name:     "videoSummarization",
+			Config:   &VideoSummarizationConfiguration{OutputAudio: true, MaxMotionThumbnailDuration: 10 * time.Second, FadeInFadeOut: true},
+			Expected: `{"options":{"fadeInFadeOut":"true","maxMotionThumbnailDurationInSecs":"10","outputAudio":"true"},"version":"1.0"}`,
+		},
+		{
+			Name:     "contentModerator",
+			Config:   &ContentModeratorConfiguration{},
+			Expected: `{"version":"2.0"}`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			actual, err := tc.Config.Configuration()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != tc.Expected {
+				t.Errorf("unexpected configuration. expected: %v, actual: %v", tc.Expected, actual)
+			}
+			var v interface{}
+			if err := json.Unmarshal([]byte(actual), &v); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	t.Run("indexer", func(t *testing.T) {
+		config := &IndexerConfiguration{Title: "sample", CaptionFormats: []string{IndexerCaptionFormatWebVTT, IndexerCaptionFormatTTML}, GenerateKeywords: true}
+		if config.MediaProcessorName() != MediaProcessorIndexer {
+			t.Errorf("unexpected media processor name: %v", config.MediaProcessorName())
+		}
+		actual, err := config.Configuration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := xml.Header + `<configuration version="2.0"><input><metadata key="title" value="sample"></metadata></input><features><feature name="ASR"><settings><add key="Language" value="English"></add><add key="CaptionFormats" value="webvtt;ttml"></add><add key="GenerateAIB" value="false"></add><add key="GenerateKeywords" value="true"></add></settings></feature></features></configuration>`
+		if actual != expected {
+			t.Errorf("unexpected configuration. expected: %v, actual: %v", expected, actual)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, config := range []AnalyticsConfiguration{
+			&IndexerConfiguration{CaptionFormats: []string{"srt"}},
+			&FaceDetectorConfiguration{Mode: "Smiles"},
+			&MotionDetectorConfiguration{SensitivityLevel: "extreme"},
+			&MotionDetectorConfiguration{DetectionZones: [][]MotionDetectionPoint{{{X: 0, Y: 0}}}},
+			&OCRConfiguration{TextOrientation: "Sideways"},
+		} {
+			if _, err := config.Configuration(); err == nil {
+				t.Errorf("accept invalid configuration: %#v", config)
+			}
+		}
+	})
+}
+
+func TestParseFaceDetectionResult(t *testing.T) {
+	raw := `{
+  "version": 1, "timescale": 30000, "offset": 0, "framerate": 29.97, "width": 1280, "height": 720,
+  "fragments": [
+    {"start": 0, "duration": 60060},
+    {"start": 60060, "duration": 60060, "interval": 30030, "events": [
+      [{"id": 0, "x": 0.25, "y": 0.1, "width": 0.2, "height": 0.3}],
+      []
+    ]}
+  ]
+}`
+	result, err := ParseFaceDetectionResult(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Fragments) != 2 || len(result.Fragments[1].Events) != 2 {
+		t.Fatalf("unexpected fragments: %#v", result.Fragments)
+	}
+	expected := FaceEvent{ID: 0, X: 0.25, Y: 0.1, Width: 0.2, Height: 0.3}
+	if actual := result.Fragments[1].Events[0][0]; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected event. expected: %#v, actual: %#v", expected, actual)
+	}
+	if actual := result.ToDuration(result.Fragments[1].Start); actual != 2002*time.Millisecond {
+		t.Errorf("unexpected start. expected: %v, actual: %v", 2002*time.Millisecond, actual)
+	}
+}
+
+func TestParseWebVTT(t *testing.T) {
+	raw := "WEBVTT\r\n\r\n1\r\n00:00:01.000 --> 00:00:04.500\r\nhello\r\nworld\r\n\r\n01:02:03.250 --> 01:02:05.000 align:start\r\nbye\r\n"
+	cues, err := ParseWebVTT(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []WebVTTCue{
+		{ID: "1", Start: time.Second, End: 4500 * time.Millisecond, Text: "hello\nworld"},
+		{Start: time.Hour + 2*time.Minute + 3250*time.Millisecond, End: time.Hour + 2*time.Minute + 5*time.Second, Text: "bye"},
+	}
+	if !reflect.DeepEqual(cues, expected) {
+		t.Errorf("unexpected cues. expected: %#v, actual: %#v", expected, cues)
+	}
+
+	if _, err := ParseWebVTT(strings.NewReader("1\n00:00:01.000 --> 00:00:02.000\nhello\n")); err == nil {
+		t.Error("accept WebVTT without header")
+	}
+}
@@ -2,9 +2,13 @@ package ams
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"path"
+	"strings"
 
+	"github.com/orisano/httpc"
 	"github.com/pkg/errors"
 )
 
@@ -55,18 +59,35 @@ func (c *Client) GetAsset(ctx context.Context, assetID string) (*Asset, error) {
 	return &out, nil
 }
 
+// GetAssets returns every asset in the account, requesting them page by page.
 func (c *Client) GetAssets(ctx context.Context) ([]Asset, error) {
+	return c.getAssets(ctx)
+}
+
+// GetAssetsWithName returns the assets named name.
+func (c *Client) GetAssetsWithName(ctx context.Context, name string) ([]Asset, error) {
+	filter := fmt.Sprintf("Name eq '%s'", strings.Replace(name, "'", "''", -1))
+	return c.getAssets(ctx, httpc.AddQuery("$filter", filter))
+}
+
+func (c *Client) getAssets(ctx context.Context, opts ...httpc.RequestOption) ([]Asset, error) {
 	c.logger.Printf("[INFO] get assets ...")
 
-	var out struct {
-		Assets []Asset `json:"value"`
-	}
-	if err := c.get(ctx, assetsEndpoint, &out); err != nil {
+	var assets []Asset
+	err := c.getList(ctx, assetsEndpoint, func(value json.RawMessage) (int, error) {
+		var page []Asset
+		if err := json.Unmarshal(value, &page); err != nil {
+			return 0, err
+		}
+		assets = append(assets, page...)
+		return len(page), nil
+	}, opts...)
+	if err != nil {
 		return nil, err
 	}
 
 	c.logger.Printf("[INFO] completed")
-	return out.Assets, nil
+	return assets, nil
 }
 
 func (c *Client) CreateAsset(ctx context.Context, name string) (*Asset, error) {
@@ -84,6 +105,22 @@ func (c *Client) CreateAsset(ctx context.Context, name string) (*Asset, error) {
 	return &out, nil
 }
 
+func (c *Client) CreateAssetWithStorageAccount(ctx context.Context, name, storageAccountName string) (*Asset, error) {
+	c.logger.Printf("[INFO] create asset [name=%#v,storageAccountName=%#v] ...", name, storageAccountName)
+
+	params := map[string]interface{}{
+		"Name":               name,
+		"StorageAccountName": storageAccountName,
+	}
+	var out Asset
+	if err := c.post(ctx, assetsEndpoint, params, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed, new asset[#%s]", out.ID)
+	return &out, nil
+}
+
 func (c *Client) GetAssetFiles(ctx context.Context, assetID string) ([]AssetFile, error) {
 	c.logger.Printf("[INFO] get asset[#%s] files ...", assetID)
 
@@ -99,6 +136,21 @@ func (c *Client) GetAssetFiles(ctx context.Context, assetID string) ([]AssetFile
 	return out.AssetFiles, nil
 }
 
+func (c *Client) GetParentAssets(ctx context.Context, assetID string) ([]Asset, error) {
+	c.logger.Printf("[INFO] get asset[#%s] parent assets ...", assetID)
+
+	endpoint := path.Join(toAssetResource(assetID), "ParentAssets")
+	var out struct {
+		Assets []Asset `json:"value"`
+	}
+	if err := c.get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.Assets, nil
+}
+
 func (c *Client) DeleteAsset(ctx context.Context, assetID string) error {
 	endpoint := toAssetResource(assetID)
 	req, err := c.newRequest(ctx, http.MethodDelete, endpoint)
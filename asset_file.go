@@ -15,7 +15,7 @@ const (
 type AssetFile struct {
 	ID              string `json:"Id"`
 	Name            string `json:"Name"`
-	ContentFileSize string `json:"ContentFileSize"`
+	ContentFileSize int64  `json:"ContentFileSize,string"`
 	ParentAssetID   string `json:"ParentAssetId"`
 	IsPrimary       bool   `json:"IsPrimary"`
 	LastModified    string `json:"LastModified"`
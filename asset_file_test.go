@@ -32,7 +32,7 @@ func TestClient_CreateAssetFile(t *testing.T) {
 			t.Error("MIMEType is required")
 		}
 
-		assetFile.ContentFileSize = "0"
+		assetFile.ContentFileSize = 0
 		assetFile.Created = formatTime(time.Now())
 		assetFile.LastModified = formatTime(time.Now())
 		assetFile.ID = "create-asset-file-id"
@@ -76,7 +76,7 @@ func TestClient_UpdateAssetFile(t *testing.T) {
 	expected := AssetFile{
 		ID:              "update-asset-file-id",
 		Name:            "demo.mp4",
-		ContentFileSize: "1024",
+		ContentFileSize: 1024,
 		ParentAssetID:   "parent-asset-id",
 		IsPrimary:       true,
 		LastModified:    formatTime(time.Now()),
@@ -109,3 +109,27 @@ func TestClient_UpdateAssetFile(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestAssetFile_ContentFileSize(t *testing.T) {
+	raw := `{"Id":"sample-asset-file-id","ContentFileSize":"100000000000000000"}`
+
+	var assetFile AssetFile
+	if err := json.Unmarshal([]byte(raw), &assetFile); err != nil {
+		t.Fatal(err)
+	}
+	if expected := int64(100000000000000000); assetFile.ContentFileSize != expected {
+		t.Errorf("unexpected ContentFileSize. expected: %v, actual: %v", expected, assetFile.ContentFileSize)
+	}
+
+	b, err := json.Marshal(&assetFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var params map[string]interface{}
+	if err := json.Unmarshal(b, &params); err != nil {
+		t.Fatal(err)
+	}
+	if actual := params["ContentFileSize"]; actual != "100000000000000000" {
+		t.Errorf("unexpected ContentFileSize. expected: %#v, actual: %#v", "100000000000000000", actual)
+	}
+}
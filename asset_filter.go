@@ -0,0 +1,89 @@
+package ams
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/orisano/httpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	assetFiltersEndpoint = "AssetFilters"
+)
+
+type AssetFilter struct {
+	ID                    string                 `json:"Id,omitempty"`
+	ParentAssetID         string                 `json:"ParentAssetId"`
+	Name                  string                 `json:"Name"`
+	PresentationTimeRange *PresentationTimeRange `json:"PresentationTimeRange,omitempty"`
+	Tracks                []FilterTrackSelect    `json:"Tracks,omitempty"`
+}
+
+func (c *Client) CreateAssetFilter(ctx context.Context, assetID, name string, presentationTimeRange *PresentationTimeRange, tracks []FilterTrackSelect) (*AssetFilter, error) {
+	c.logger.Printf("[INFO] create asset[#%s] filter [name=%#v] ...", assetID, name)
+
+	params := &AssetFilter{
+		ParentAssetID:         assetID,
+		Name:                  name,
+		PresentationTimeRange: presentationTimeRange,
+		Tracks:                tracks,
+	}
+	var out AssetFilter
+	if err := c.post(ctx, assetFiltersEndpoint, params, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed, new asset[#%s] filter[#%s]", assetID, out.ID)
+	return &out, nil
+}
+
+func (c *Client) GetAssetFilters(ctx context.Context, assetID string) ([]AssetFilter, error) {
+	c.logger.Printf("[INFO] get asset[#%s] filters ...", assetID)
+
+	endpoint := path.Join(toAssetResource(assetID), assetFiltersEndpoint)
+	var out struct {
+		AssetFilters []AssetFilter `json:"value"`
+	}
+	if err := c.get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.AssetFilters, nil
+}
+
+func (c *Client) UpdateAssetFilter(ctx context.Context, assetFilter *AssetFilter) error {
+	endpoint := toAssetFilterResource(assetFilter.ID)
+	req, err := c.newRequest(ctx, "MERGE", endpoint, httpc.WithJSON(assetFilter))
+	if err != nil {
+		return errors.Wrap(err, "request build failed")
+	}
+
+	c.logger.Printf("[INFO] update asset[#%s] filter[#%s] ...", assetFilter.ParentAssetID, assetFilter.ID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+func (c *Client) DeleteAssetFilter(ctx context.Context, assetFilterID string) error {
+	endpoint := toAssetFilterResource(assetFilterID)
+	req, err := c.newRequest(ctx, http.MethodDelete, endpoint)
+	if err != nil {
+		return errors.Wrap(err, "request build failed")
+	}
+
+	c.logger.Printf("[INFO] delete asset filter #%s ...", assetFilterID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+func toAssetFilterResource(assetFilterID string) string {
+	return toResource(assetFiltersEndpoint, assetFilterID)
+}
@@ -0,0 +1,104 @@
+package ams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_CreateAssetFilter(t *testing.T) {
+	assetID := "nb:cid:UUID:sample-asset-id"
+	name := "FirstMinute"
+	presentationTimeRange := &PresentationTimeRange{
+		EndTimestamp: 600000000,
+		Timescale:    10000000,
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc("/AssetFilters", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodPost)
+		testAMSHeader(t, r, false)
+
+		var assetFilter AssetFilter
+		if err := json.NewDecoder(r.Body).Decode(&assetFilter); err != nil {
+			t.Fatal(err)
+		}
+		if assetFilter.ParentAssetID != assetID {
+			t.Errorf("unexpected ParentAssetId. expected: %v, actual: %v", assetID, assetFilter.ParentAssetID)
+		}
+		if assetFilter.Name != name {
+			t.Errorf("unexpected Name. expected: %v, actual: %v", name, assetFilter.Name)
+		}
+		if !reflect.DeepEqual(assetFilter.PresentationTimeRange, presentationTimeRange) {
+			t.Errorf("unexpected PresentationTimeRange. expected: %#v, actual: %#v", presentationTimeRange, assetFilter.PresentationTimeRange)
+		}
+		assetFilter.ID = assetID + "__" + name
+
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(assetFilter); err != nil {
+			t.Fatal(err)
+		}
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	assetFilter, err := client.CreateAssetFilter(context.TODO(), assetID, name, presentationTimeRange, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assetFilter.ID) == 0 {
+		t.Error("missing asset filter id")
+	}
+}
+
+func TestClient_GetAssetFilters(t *testing.T) {
+	assetID := "sample-asset-id"
+	expected := []AssetFilter{
+		{
+			ID:            "sample-asset-filter-id",
+			ParentAssetID: assetID,
+			Name:          "AudioOnly",
+			Tracks: []FilterTrackSelect{
+				NewFilterTrackSelect(FilterTrackType(FilterTrackTypeAudio)),
+			},
+		},
+	}
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/Assets('%v')/AssetFilters", assetID),
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue(expected)),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	actual, err := client.GetAssetFilters(context.TODO(), assetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected asset filters. expected: %#v, actual: %#v", expected, actual)
+	}
+}
+
+func TestClient_DeleteAssetFilter(t *testing.T) {
+	assetFilterID := "sample-asset-filter-id"
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/AssetFilters('%v')", assetFilterID),
+		testJSONHandler(t, http.MethodDelete, false, http.StatusNoContent, nil),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	if err := client.DeleteAssetFilter(context.TODO(), assetFilterID); err != nil {
+		t.Error(err)
+	}
+}
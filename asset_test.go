@@ -116,13 +116,51 @@ func TestClient_CreateAsset(t *testing.T) {
 	}
 }
 
+func TestClient_CreateAssetWithStorageAccount(t *testing.T) {
+	storageAccountName := "samplestorage2"
+	m := http.NewServeMux()
+	m.HandleFunc("/Assets", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodPost)
+		testAMSHeader(t, r, false)
+
+		var asset Asset
+		if err := json.NewDecoder(r.Body).Decode(&asset); err != nil {
+			t.Fatal(err)
+		}
+		if asset.StorageAccountName != storageAccountName {
+			t.Errorf("unexpected StorageAccountName. expected: %#v, actual: %#v", storageAccountName, asset.StorageAccountName)
+		}
+		asset.ID = "created-id"
+		asset.Created = formatTime(time.Now())
+		asset.LastModified = formatTime(time.Now())
+
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(asset); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	asset, err := client.CreateAssetWithStorageAccount(context.TODO(), "sample", storageAccountName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.StorageAccountName != storageAccountName {
+		t.Errorf("unexpected StorageAccountName. expected: %#v, actual: %#v", storageAccountName, asset.StorageAccountName)
+	}
+}
+
 func TestClient_GetAssetFiles(t *testing.T) {
 	assetID := "test-asset-id"
 	expected := []AssetFile{
 		{
 			ID:              "asset-file-1",
 			Name:            "sample1",
-			ContentFileSize: "0",
+			ContentFileSize: 0,
 			ParentAssetID:   assetID,
 			IsPrimary:       false,
 			LastModified:    formatTime(time.Now()),
@@ -133,7 +171,7 @@ func TestClient_GetAssetFiles(t *testing.T) {
 		{
 			ID:              "asset-file-2",
 			Name:            "sample2",
-			ContentFileSize: "100000000000000000",
+			ContentFileSize: 100000000000000000,
 			ParentAssetID:   assetID,
 			IsPrimary:       true,
 			LastModified:    formatTime(time.Now()),
@@ -160,6 +198,29 @@ func TestClient_GetAssetFiles(t *testing.T) {
 	}
 }
 
+func TestClient_GetParentAssets(t *testing.T) {
+	assetID := "encoded-asset-id"
+	expected := []Asset{
+		testAsset("source-asset-id", "Source"),
+	}
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/Assets('%v')/ParentAssets", assetID),
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue(expected)),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	actual, err := client.GetParentAssets(context.TODO(), assetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected parent assets. expected: %#v, actual: %#v", expected, actual)
+	}
+}
+
 func TestClient_DeleteAsset(t *testing.T) {
 	assetID := "delete-asset-id"
 	m := http.NewServeMux()
@@ -53,3 +53,17 @@ func (c *Client) PutBlockList(ctx context.Context, uploadURL *url.URL, blockList
 	}
 	return sasc.PutBlockList(ctx, blockList)
 }
+
+func (c *Client) GetBlob(ctx context.Context, downloadURL *url.URL) (io.ReadCloser, error) {
+	if ctx == nil {
+		return nil, errors.New("missing ctx")
+	}
+	if downloadURL == nil {
+		return nil, errors.New("missing downloadURL")
+	}
+	sasc, err := c.NewSASClient(downloadURL.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to construct SASClient")
+	}
+	return sasc.GetBlob(ctx)
+}
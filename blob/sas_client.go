@@ -142,6 +142,32 @@ func (c *SASClient) PutBlockList(ctx context.Context, blockList []string) error
 	return nil
 }
 
+func (c *SASClient) GetBlob(ctx context.Context) (io.ReadCloser, error) {
+	if ctx == nil {
+		return nil, errors.New("missing ctx")
+	}
+	req, err := c.rb.NewRequest(ctx, http.MethodGet, "",
+		withDate(c.TimeNow()),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to construct http request")
+	}
+	c.logger.Print("[INFO] get blob ...")
+	resp, err := httpc.Retry(c.httpClient, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to http request")
+	}
+
+	if got := resp.StatusCode; got != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		return nil, errors.Errorf("unexpected status code. expected: %v, but got: %v", http.StatusOK, got)
+	}
+
+	c.logger.Print("[INFO] completed")
+	return resp.Body, nil
+}
+
 type UploadError struct {
 	errs []error
 	m    *sync.Mutex
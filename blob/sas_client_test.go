@@ -155,3 +155,38 @@ func TestClient_PutBlockList(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestClient_GetBlob(t *testing.T) {
+	expected := []byte("sample blob content")
+
+	m := http.NewServeMux()
+	m.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected http method. expected: %v, got: %v", http.MethodGet, r.Method)
+		}
+		if got := r.Header.Get("x-ms-version"); got != APIVersion {
+			t.Errorf("unexpected x-ms-version header. expected: %v, got: %v", APIVersion, got)
+		}
+		w.Write(expected)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client, err := NewSASClient(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob, err := client.GetBlob(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blob.Close()
+
+	got, err := ioutil.ReadAll(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Compare(got, expected) != 0 {
+		t.Errorf("unexpected blob. expected: %v, got: %v", string(expected), string(got))
+	}
+}
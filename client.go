@@ -11,6 +11,7 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"time"
 
 	"github.com/orisano/httpc"
 	"github.com/pkg/errors"
@@ -24,14 +25,18 @@ const (
 	version               = "0.3.0"
 )
 
+// listPageSize is the maximum number of entities AMS returns for a list request.
+const listPageSize = 1000
+
 var (
 	defaultUserAgent = fmt.Sprintf("Go/%s (%s-%s) go-ams/%s", runtime.Version(), runtime.GOARCH, runtime.GOOS, version)
 )
 
 type clientOptions struct {
-	UserAgent string
-	Logger    *log.Logger
-	Debug     bool
+	UserAgent              string
+	Logger                 *log.Logger
+	Debug                  bool
+	MediaProcessorCacheTTL time.Duration
 }
 
 type clientOption func(*clientOptions)
@@ -54,6 +59,14 @@ func SetDebug(debug bool) clientOption {
 	}
 }
 
+// SetMediaProcessorCacheTTL sets how long ResolveMediaProcessor caches the media processors (default: 1 hour).
+// Zero disables the cache.
+func SetMediaProcessorCacheTTL(ttl time.Duration) clientOption {
+	return func(options *clientOptions) {
+		options.MediaProcessorCacheTTL = ttl
+	}
+}
+
 type Client struct {
 	rb *httpc.RequestBuilder
 
@@ -62,6 +75,8 @@ type Client struct {
 	userAgent string
 	logger    *log.Logger
 	debug     bool
+
+	mediaProcessors *mediaProcessorCache
 }
 
 func NewClient(urlStr string, authorizedClient *http.Client, opts ...clientOption) (*Client, error) {
@@ -70,7 +85,8 @@ func NewClient(urlStr string, authorizedClient *http.Client, opts ...clientOptio
 	}
 
 	options := &clientOptions{
-		UserAgent: defaultUserAgent,
+		UserAgent:              defaultUserAgent,
+		MediaProcessorCacheTTL: defaultMediaProcessorCacheTTL,
 	}
 	for _, opt := range opts {
 		opt(options)
@@ -112,6 +128,8 @@ func NewClient(urlStr string, authorizedClient *http.Client, opts ...clientOptio
 		userAgent: options.UserAgent,
 		logger:    logger,
 		debug:     debug,
+
+		mediaProcessors: &mediaProcessorCache{ttl: options.MediaProcessorCacheTTL},
 	}, nil
 }
 
@@ -141,8 +159,8 @@ func (c *Client) do(req *http.Request, expectedCode int, out interface{}) error
 	return nil
 }
 
-func (c *Client) get(ctx context.Context, spath string, out interface{}) error {
-	req, err := c.newRequest(ctx, http.MethodGet, spath)
+func (c *Client) get(ctx context.Context, spath string, out interface{}, opts ...httpc.RequestOption) error {
+	req, err := c.newRequest(ctx, http.MethodGet, spath, opts...)
 	if err != nil {
 		return errors.Wrap(err, "failed to construct GET request")
 	}
@@ -152,6 +170,30 @@ func (c *Client) get(ctx context.Context, spath string, out interface{}) error {
 	return nil
 }
 
+// getList GETs every page of the entity set at spath with $skip and $top, since AMS truncates a list at listPageSize.
+// appendPage decodes the "value" of each page and returns the number of entities in it.
+func (c *Client) getList(ctx context.Context, spath string, appendPage func(value json.RawMessage) (int, error), opts ...httpc.RequestOption) error {
+	for skip := 0; ; skip += listPageSize {
+		pageOpts := append(opts[:len(opts):len(opts)],
+			httpc.AddQuery("$skip", fmt.Sprint(skip)),
+			httpc.AddQuery("$top", fmt.Sprint(listPageSize)),
+		)
+		var out struct {
+			Value json.RawMessage `json:"value"`
+		}
+		if err := c.get(ctx, spath, &out, pageOpts...); err != nil {
+			return err
+		}
+		n, err := appendPage(out.Value)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode page")
+		}
+		if n < listPageSize {
+			return nil
+		}
+	}
+}
+
 func (c *Client) post(ctx context.Context, spath string, in interface{}, out interface{}, opts ...httpc.RequestOption) error {
 	opts = append(opts, httpc.WithJSON(in))
 	req, err := c.newRequest(ctx, http.MethodPost, spath, opts...)
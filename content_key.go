@@ -0,0 +1,294 @@
+package ams
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/orisano/httpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	contentKeysEndpoint = "ContentKeys"
+	contentKeyIDPrefix  = "nb:kid:UUID:"
+	contentKeySize      = 16
+)
+
+const (
+	ContentKeyTypeCommonEncryption        = 0
+	ContentKeyTypeStorageEncryption       = 1
+	ContentKeyTypeConfigurationEncryption = 2
+	ContentKeyTypeEnvelopeEncryption      = 4
+	ContentKeyTypeCommonEncryptionCbcs    = 6
+)
+
+const (
+	ProtectionKeyTypeX509CertificateThumbprint = 0
+)
+
+type ContentKey struct {
+	ID                    string `json:"Id"`
+	Name                  string `json:"Name"`
+	Created               string `json:"Created"`
+	LastModified          string `json:"LastModified"`
+	ContentKeyType        int    `json:"ContentKeyType"`
+	EncryptedContentKey   string `json:"EncryptedContentKey"`
+	ProtectionKeyID       string `json:"ProtectionKeyId"`
+	ProtectionKeyType     int    `json:"ProtectionKeyType"`
+	Checksum              string `json:"Checksum"`
+	AuthorizationPolicyID string `json:"AuthorizationPolicyId,omitempty"`
+}
+
+// GetProtectionKeyID returns the id of the X.509 certificate which encrypts content keys of contentKeyType.
+func (c *Client) GetProtectionKeyID(ctx context.Context, contentKeyType int) (string, error) {
+	c.logger.Printf("[INFO] get protection key id [type=%d] ...", contentKeyType)
+
+	var out struct {
+		Value string `json:"value"`
+	}
+	if err := c.get(ctx, "GetProtectionKeyId", &out, httpc.AddQuery("contentKeyType", fmt.Sprint(contentKeyType))); err != nil {
+		return "", err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.Value, nil
+}
+
+// GetProtectionKey returns the X.509 certificate of the protection key.
+func (c *Client) GetProtectionKey(ctx context.Context, protectionKeyID string) (*x509.Certificate, error) {
+	c.logger.Printf("[INFO] get protection key #%s ...", protectionKeyID)
+
+	var out struct {
+		Value string `json:"value"`
+	}
+	if err := c.get(ctx, "GetProtectionKey", &out, httpc.AddQuery("ProtectionKeyId", fmt.Sprintf("'%s'", protectionKeyID))); err != nil {
+		return nil, err
+	}
+	der, err := base64.StdEncoding.DecodeString(out.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode protection key")
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse protection key")
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return cert, nil
+}
+
+// GenerateContentKey returns a random AES-128 key.
+func GenerateContentKey() ([]byte, error) {
+	key := make([]byte, contentKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Wrap(err, "failed to generate content key")
+	}
+	return key, nil
+}
+
+// EncryptContentKey encrypts key with the public key of the protection key by RSA-OAEP (SHA-1), as AMS expects,
+// and returns it base64 encoded.
+func EncryptContentKey(cert *x509.Certificate, key []byte) (string, error) {
+	if cert == nil {
+		return "", errors.New("missing cert")
+	}
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return "", errors.Errorf("unsupported public key type %T", cert.PublicKey)
+	}
+	encrypted, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, pub, key, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encrypt content key")
+	}
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+// ContentKeyChecksum returns the checksum of key, which is the first 8 bytes of the content key id
+// encrypted with key by AES-ECB, base64 encoded. The id is in the byte order of .NET Guid.ToByteArray.
+func ContentKeyChecksum(contentKeyID string, key []byte) (string, error) {
+	guid, err := guidBytes(strings.TrimPrefix(contentKeyID, contentKeyIDPrefix))
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid content key")
+	}
+	encrypted := make([]byte, aes.BlockSize)
+	block.Encrypt(encrypted, guid)
+	return base64.StdEncoding.EncodeToString(encrypted[:8]), nil
+}
+
+// guidBytes converts a GUID string to bytes in the order of .NET Guid.ToByteArray,
+// whose first three groups are little endian.
+func guidBytes(guid string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.Replace(guid, "-", "", -1))
+	if err != nil || len(b) != 16 {
+		return nil, errors.Errorf("invalid GUID '%v'", guid)
+	}
+	b[0], b[1], b[2], b[3] = b[3], b[2], b[1], b[0]
+	b[4], b[5] = b[5], b[4]
+	b[6], b[7] = b[7], b[6]
+	return b, nil
+}
+
+// CreateContentKey creates a content key of contentKeyType with a random AES key.
+func (c *Client) CreateContentKey(ctx context.Context, name string, contentKeyType int) (*ContentKey, error) {
+	key, err := GenerateContentKey()
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateContentKeyWithValue(ctx, name, contentKeyType, key)
+}
+
+// CreateContentKeyWithValue creates a content key of contentKeyType with key.
+// key is encrypted with the protection key of the account before it is sent.
+func (c *Client) CreateContentKeyWithValue(ctx context.Context, name string, contentKeyType int, key []byte) (*ContentKey, error) {
+	switch contentKeyType {
+	case ContentKeyTypeCommonEncryption, ContentKeyTypeEnvelopeEncryption, ContentKeyTypeCommonEncryptionCbcs:
+	default:
+		return nil, errors.Errorf("unsupported content key type %d", contentKeyType)
+	}
+	if len(key) != contentKeySize {
+		return nil, errors.Errorf("key must be %d bytes, but got %d", contentKeySize, len(key))
+	}
+
+	protectionKeyID, err := c.GetProtectionKeyID(ctx, contentKeyType)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get protection key id")
+	}
+	cert, err := c.GetProtectionKey(ctx, protectionKeyID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get protection key")
+	}
+	encryptedContentKey, err := EncryptContentKey(cert, key)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newUUID()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate content key id")
+	}
+	contentKeyID := contentKeyIDPrefix + id
+	checksum, err := ContentKeyChecksum(contentKeyID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"Id":                  contentKeyID,
+		"Name":                name,
+		"ContentKeyType":      contentKeyType,
+		"EncryptedContentKey": encryptedContentKey,
+		"ProtectionKeyId":     protectionKeyID,
+		"ProtectionKeyType":   ProtectionKeyTypeX509CertificateThumbprint,
+		"Checksum":            checksum,
+	}
+
+	c.logger.Printf("[INFO] create content key [name=%#v,type=%d] ...", name, contentKeyType)
+	var out ContentKey
+	if err := c.post(ctx, contentKeysEndpoint, params, &out); err != nil {
+		return nil, err
+	}
+	c.logger.Printf("[INFO] completed, new content key[#%s]", out.ID)
+	return &out, nil
+}
+
+func (c *Client) GetContentKey(ctx context.Context, contentKeyID string) (*ContentKey, error) {
+	c.logger.Printf("[INFO] get content key #%s ...", contentKeyID)
+
+	var out ContentKey
+	if err := c.get(ctx, toContentKeyResource(contentKeyID), &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return &out, nil
+}
+
+func (c *Client) GetContentKeys(ctx context.Context) ([]ContentKey, error) {
+	return c.getContentKeys(ctx, contentKeysEndpoint)
+}
+
+func (c *Client) GetAssetContentKeys(ctx context.Context, assetID string) ([]ContentKey, error) {
+	endpoint := path.Join(toAssetResource(assetID), contentKeysEndpoint)
+	return c.getContentKeys(ctx, endpoint)
+}
+
+func (c *Client) getContentKeys(ctx context.Context, endpoint string) ([]ContentKey, error) {
+	c.logger.Printf("[INFO] get content keys ...")
+
+	var out struct {
+		ContentKeys []ContentKey `json:"value"`
+	}
+	if err := c.get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.ContentKeys, nil
+}
+
+func (c *Client) DeleteContentKey(ctx context.Context, contentKeyID string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, toContentKeyResource(contentKeyID))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] delete content key #%s ...", contentKeyID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+// LinkContentKey associates the content key with the asset, which is required before the asset is delivered encrypted.
+func (c *Client) LinkContentKey(ctx context.Context, assetID, contentKeyID string) error {
+	params := map[string]interface{}{
+		"uri": c.buildContentKeyURI(contentKeyID),
+	}
+	endpoint := path.Join(toAssetResource(assetID), "$links", contentKeysEndpoint)
+	req, err := c.newRequest(ctx, http.MethodPost, endpoint, httpc.WithJSON(params))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] link content key #%s to asset #%s ...", contentKeyID, assetID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+// UnlinkContentKey removes the association between the content key and the asset. The content key itself is kept.
+func (c *Client) UnlinkContentKey(ctx context.Context, assetID, contentKeyID string) error {
+	endpoint := path.Join(toAssetResource(assetID), "$links", toContentKeyResource(contentKeyID))
+	req, err := c.newRequest(ctx, http.MethodDelete, endpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] unlink content key #%s from asset #%s ...", contentKeyID, assetID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+func toContentKeyResource(contentKeyID string) string {
+	return toResource(contentKeysEndpoint, contentKeyID)
+}
+
+func (c *Client) buildContentKeyURI(contentKeyID string) string {
+	return c.buildURI(toContentKeyResource(contentKeyID))
+}
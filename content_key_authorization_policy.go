@@ -0,0 +1,267 @@
+package ams
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/orisano/httpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	contentKeyAuthorizationPoliciesEndpoint      = "ContentKeyAuthorizationPolicies"
+	contentKeyAuthorizationPolicyOptionsEndpoint = "ContentKeyAuthorizationPolicyOptions"
+	contentKeyAuthorizationPolicyLinkedOptions   = "Options"
+)
+
+const (
+	KeyDeliveryTypeNone = iota
+	KeyDeliveryTypePlayReadyLicense
+	KeyDeliveryTypeBaselineHTTP
+	KeyDeliveryTypeWidevine
+	KeyDeliveryTypeFairPlay
+)
+
+// ContentKeyAuthorizationPolicy decides who gets the content keys which it is linked to.
+// The key is delivered if the client meets the restrictions of any of its options.
+type ContentKeyAuthorizationPolicy struct {
+	ID   string `json:"Id"`
+	Name string `json:"Name"`
+}
+
+// ContentKeyAuthorizationPolicyOption is a way to deliver the content key (KeyDeliveryType*) and its restrictions.
+// KeyDeliveryConfiguration is the license template of PlayReady, Widevine and FairPlay; it is empty for BaselineHTTP (AES).
+type ContentKeyAuthorizationPolicyOption struct {
+	ID                       string                                     `json:"Id"`
+	Name                     string                                     `json:"Name"`
+	KeyDeliveryType          int                                        `json:"KeyDeliveryType"`
+	KeyDeliveryConfiguration string                                     `json:"KeyDeliveryConfiguration"`
+	Restrictions             []ContentKeyAuthorizationPolicyRestriction `json:"Restrictions"`
+}
+
+func (c *Client) CreateContentKeyAuthorizationPolicy(ctx context.Context, name string) (*ContentKeyAuthorizationPolicy, error) {
+	c.logger.Printf("[INFO] create content key authorization policy [name=%#v] ...", name)
+
+	params := map[string]interface{}{
+		"Name": name,
+	}
+	var out ContentKeyAuthorizationPolicy
+	if err := c.post(ctx, contentKeyAuthorizationPoliciesEndpoint, params, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed, new content key authorization policy[#%s]", out.ID)
+	return &out, nil
+}
+
+func (c *Client) GetContentKeyAuthorizationPolicy(ctx context.Context, policyID string) (*ContentKeyAuthorizationPolicy, error) {
+	c.logger.Printf("[INFO] get content key authorization policy #%s ...", policyID)
+
+	var out ContentKeyAuthorizationPolicy
+	if err := c.get(ctx, toContentKeyAuthorizationPolicyResource(policyID), &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return &out, nil
+}
+
+func (c *Client) GetContentKeyAuthorizationPolicies(ctx context.Context) ([]ContentKeyAuthorizationPolicy, error) {
+	c.logger.Printf("[INFO] get content key authorization policies ...")
+
+	var out struct {
+		ContentKeyAuthorizationPolicies []ContentKeyAuthorizationPolicy `json:"value"`
+	}
+	if err := c.get(ctx, contentKeyAuthorizationPoliciesEndpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.ContentKeyAuthorizationPolicies, nil
+}
+
+func (c *Client) UpdateContentKeyAuthorizationPolicy(ctx context.Context, policyID, name string) error {
+	params := map[string]interface{}{
+		"Name": name,
+	}
+	req, err := c.newRequest(ctx, "MERGE", toContentKeyAuthorizationPolicyResource(policyID), httpc.WithJSON(params))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] update content key authorization policy #%s ...", policyID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+// DeleteContentKeyAuthorizationPolicy deletes the policy. Its options are kept and have to be deleted separately.
+func (c *Client) DeleteContentKeyAuthorizationPolicy(ctx context.Context, policyID string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, toContentKeyAuthorizationPolicyResource(policyID))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] delete content key authorization policy #%s ...", policyID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+func (c *Client) CreateContentKeyAuthorizationPolicyOption(ctx context.Context, name string, keyDeliveryType int, keyDeliveryConfiguration string, restrictions []ContentKeyAuthorizationPolicyRestriction) (*ContentKeyAuthorizationPolicyOption, error) {
+	switch keyDeliveryType {
+	case KeyDeliveryTypePlayReadyLicense, KeyDeliveryTypeBaselineHTTP, KeyDeliveryTypeWidevine, KeyDeliveryTypeFairPlay:
+	default:
+		return nil, errors.Errorf("unknown key delivery type %d", keyDeliveryType)
+	}
+	if len(restrictions) == 0 {
+		return nil, errors.New("missing restrictions")
+	}
+	c.logger.Printf("[INFO] create content key authorization policy option [name=%#v] ...", name)
+
+	params := map[string]interface{}{
+		"Name":                     name,
+		"KeyDeliveryType":          keyDeliveryType,
+		"KeyDeliveryConfiguration": keyDeliveryConfiguration,
+		"Restrictions":             restrictions,
+	}
+	var out ContentKeyAuthorizationPolicyOption
+	if err := c.post(ctx, contentKeyAuthorizationPolicyOptionsEndpoint, params, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed, new content key authorization policy option[#%s]", out.ID)
+	return &out, nil
+}
+
+func (c *Client) GetContentKeyAuthorizationPolicyOption(ctx context.Context, optionID string) (*ContentKeyAuthorizationPolicyOption, error) {
+	c.logger.Printf("[INFO] get content key authorization policy option #%s ...", optionID)
+
+	var out ContentKeyAuthorizationPolicyOption
+	if err := c.get(ctx, toContentKeyAuthorizationPolicyOptionResource(optionID), &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return &out, nil
+}
+
+func (c *Client) GetContentKeyAuthorizationPolicyOptions(ctx context.Context) ([]ContentKeyAuthorizationPolicyOption, error) {
+	return c.getContentKeyAuthorizationPolicyOptions(ctx, contentKeyAuthorizationPolicyOptionsEndpoint)
+}
+
+// GetContentKeyAuthorizationPolicyLinkedOptions returns the options linked to the policy.
+func (c *Client) GetContentKeyAuthorizationPolicyLinkedOptions(ctx context.Context, policyID string) ([]ContentKeyAuthorizationPolicyOption, error) {
+	endpoint := path.Join(toContentKeyAuthorizationPolicyResource(policyID), contentKeyAuthorizationPolicyLinkedOptions)
+	return c.getContentKeyAuthorizationPolicyOptions(ctx, endpoint)
+}
+
+func (c *Client) getContentKeyAuthorizationPolicyOptions(ctx context.Context, endpoint string) ([]ContentKeyAuthorizationPolicyOption, error) {
+	c.logger.Printf("[INFO] get content key authorization policy options ...")
+
+	var out struct {
+		ContentKeyAuthorizationPolicyOptions []ContentKeyAuthorizationPolicyOption `json:"value"`
+	}
+	if err := c.get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.ContentKeyAuthorizationPolicyOptions, nil
+}
+
+// UpdateContentKeyAuthorizationPolicyOption replaces the name, the delivery configuration and the restrictions of the option.
+func (c *Client) UpdateContentKeyAuthorizationPolicyOption(ctx context.Context, optionID, name, keyDeliveryConfiguration string, restrictions []ContentKeyAuthorizationPolicyRestriction) error {
+	if len(restrictions) == 0 {
+		return errors.New("missing restrictions")
+	}
+	params := map[string]interface{}{
+		"Name":                     name,
+		"KeyDeliveryConfiguration": keyDeliveryConfiguration,
+		"Restrictions":             restrictions,
+	}
+	req, err := c.newRequest(ctx, "MERGE", toContentKeyAuthorizationPolicyOptionResource(optionID), httpc.WithJSON(params))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] update content key authorization policy option #%s ...", optionID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+func (c *Client) DeleteContentKeyAuthorizationPolicyOption(ctx context.Context, optionID string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, toContentKeyAuthorizationPolicyOptionResource(optionID))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] delete content key authorization policy option #%s ...", optionID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+// LinkContentKeyAuthorizationPolicyOption adds the option to the policy.
+func (c *Client) LinkContentKeyAuthorizationPolicyOption(ctx context.Context, policyID, optionID string) error {
+	params := map[string]interface{}{
+		"uri": c.buildURI(toContentKeyAuthorizationPolicyOptionResource(optionID)),
+	}
+	endpoint := path.Join(toContentKeyAuthorizationPolicyResource(policyID), "$links", contentKeyAuthorizationPolicyLinkedOptions)
+	req, err := c.newRequest(ctx, http.MethodPost, endpoint, httpc.WithJSON(params))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] link content key authorization policy option #%s to policy #%s ...", optionID, policyID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+// UnlinkContentKeyAuthorizationPolicyOption removes the option from the policy. The option itself is kept.
+func (c *Client) UnlinkContentKeyAuthorizationPolicyOption(ctx context.Context, policyID, optionID string) error {
+	endpoint := path.Join(toContentKeyAuthorizationPolicyResource(policyID), "$links", toResource(contentKeyAuthorizationPolicyLinkedOptions, optionID))
+	req, err := c.newRequest(ctx, http.MethodDelete, endpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] unlink content key authorization policy option #%s from policy #%s ...", optionID, policyID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+// SetContentKeyAuthorizationPolicy applies the policy to the content key, replacing the current one.
+func (c *Client) SetContentKeyAuthorizationPolicy(ctx context.Context, contentKeyID, policyID string) error {
+	params := map[string]interface{}{
+		"AuthorizationPolicyId": policyID,
+	}
+	req, err := c.newRequest(ctx, "MERGE", toContentKeyResource(contentKeyID), httpc.WithJSON(params))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] set content key authorization policy #%s to content key #%s ...", policyID, contentKeyID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+func toContentKeyAuthorizationPolicyResource(policyID string) string {
+	return toResource(contentKeyAuthorizationPoliciesEndpoint, policyID)
+}
+
+func toContentKeyAuthorizationPolicyOptionResource(optionID string) string {
+	return toResource(contentKeyAuthorizationPolicyOptionsEndpoint, optionID)
+}
@@ -0,0 +1,127 @@
+package ams
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_CreateContentKeyAuthorizationPolicyOption(t *testing.T) {
+	ipRestriction, err := NewIPRestriction("office", "192.0.2.1", "198.51.100.7/24", "2001:DB8::1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	restrictions := []ContentKeyAuthorizationPolicyRestriction{
+		NewOpenRestriction("open"),
+		NewTokenRestriction("token", "token-requirements"),
+		ipRestriction,
+	}
+	m := http.NewServeMux()
+	m.HandleFunc("/ContentKeyAuthorizationPolicyOptions", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodPost)
+		testAMSHeader(t, r, false)
+
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var params ContentKeyAuthorizationPolicyOption
+		if err := json.Unmarshal(b, &params); err != nil {
+			t.Fatal(err)
+		}
+		var body struct {
+			Restrictions json.RawMessage
+		}
+		if err := json.Unmarshal(b, &body); err != nil {
+			t.Fatal(err)
+		}
+		if params.Name != "aes" || params.KeyDeliveryType != KeyDeliveryTypeBaselineHTTP {
+			t.Errorf("unexpected params: %#v", params)
+		}
+		expected := `[{"Name":"open","KeyRestrictionType":0},{"Name":"token","KeyRestrictionType":1,"Requirements":"token-requirements"},{"Name":"office","KeyRestrictionType":2,"Requirements":"192.0.2.1,198.51.100.0/24,2001:db8::1"}]`
+		if string(body.Restrictions) != expected {
+			t.Errorf("unexpected restrictions. expected: %v, actual: %v", expected, string(body.Restrictions))
+		}
+		params.ID = "nb:ckpoid:UUID:option"
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(params)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	option, err := client.CreateContentKeyAuthorizationPolicyOption(context.TODO(), "aes", KeyDeliveryTypeBaselineHTTP, "", restrictions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if option.ID != "nb:ckpoid:UUID:option" {
+		t.Errorf("unexpected id: %v", option.ID)
+	}
+
+	if _, err := client.CreateContentKeyAuthorizationPolicyOption(context.TODO(), "aes", KeyDeliveryTypeBaselineHTTP, "", nil); err == nil {
+		t.Error("expected error for missing restrictions")
+	}
+}
+
+func TestClient_LinkContentKeyAuthorizationPolicy(t *testing.T) {
+	policyID := "nb:ckpid:UUID:policy"
+	optionID := "nb:ckpoid:UUID:option"
+	contentKeyID := "nb:kid:UUID:key"
+
+	var requests []string
+	m := http.NewServeMux()
+	m.HandleFunc("/ContentKeyAuthorizationPolicies('nb:ckpid:UUID:policy')/$links/Options", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodPost)
+		testAMSHeader(t, r, false)
+
+		var params map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatal(err)
+		}
+		if uri, _ := url.PathUnescape(params["uri"]); !strings.HasSuffix(uri, "/ContentKeyAuthorizationPolicyOptions('nb:ckpoid:UUID:option')") {
+			t.Errorf("unexpected uri: %v", params["uri"])
+		}
+		requests = append(requests, "link")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	m.HandleFunc("/ContentKeyAuthorizationPolicies('nb:ckpid:UUID:policy')/$links/Options('nb:ckpoid:UUID:option')", func(w http.ResponseWriter, r *http.Request) {
+		testJSONHandler(t, http.MethodDelete, false, http.StatusNoContent, nil)(w, r)
+		requests = append(requests, "unlink")
+	})
+	m.HandleFunc("/ContentKeys('nb:kid:UUID:key')", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "MERGE")
+		testAMSHeader(t, r, false)
+
+		var params map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatal(err)
+		}
+		if params["AuthorizationPolicyId"] != policyID {
+			t.Errorf("unexpected AuthorizationPolicyId: %v", params["AuthorizationPolicyId"])
+		}
+		requests = append(requests, "set")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	if err := client.LinkContentKeyAuthorizationPolicyOption(context.TODO(), policyID, optionID); err != nil {
+		t.Error(err)
+	}
+	if err := client.SetContentKeyAuthorizationPolicy(context.TODO(), contentKeyID, policyID); err != nil {
+		t.Error(err)
+	}
+	if err := client.UnlinkContentKeyAuthorizationPolicyOption(context.TODO(), policyID, optionID); err != nil {
+		t.Error(err)
+	}
+	if expected := []string{"link", "set", "unlink"}; !reflect.DeepEqual(requests, expected) {
+		t.Errorf("unexpected requests. expected: %v, actual: %v", expected, requests)
+	}
+}
@@ -0,0 +1,188 @@
+package ams
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testProtectionKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "protection key"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv, der
+}
+
+func TestContentKeyChecksum(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	keyID := "nb:kid:UUID:00112233-4455-6677-8899-aabbccddeeff"
+
+	actual, err := ContentKeyChecksum(keyID, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// .NET Guid.ToByteArray order
+	guid := []byte{0x33, 0x22, 0x11, 0x00, 0x55, 0x44, 0x77, 0x66, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted := make([]byte, aes.BlockSize)
+	block.Encrypt(encrypted, guid)
+	if expected := base64.StdEncoding.EncodeToString(encrypted[:8]); actual != expected {
+		t.Errorf("unexpected checksum. expected: %v, actual: %v", expected, actual)
+	}
+
+	if _, err := ContentKeyChecksum("nb:kid:UUID:invalid", key); err == nil {
+		t.Error("expected error for invalid id")
+	}
+}
+
+func TestClient_CreateContentKeyWithValue(t *testing.T) {
+	priv, der := testProtectionKey(t)
+	protectionKeyID := "protection-key-id"
+	key := []byte("0123456789abcdef")
+
+	m := http.NewServeMux()
+	m.HandleFunc("/GetProtectionKeyId", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodGet)
+		if got := r.URL.Query().Get("contentKeyType"); got != "4" {
+			t.Errorf("unexpected contentKeyType: %v", got)
+		}
+		json.NewEncoder(w).Encode(testWrapValue(protectionKeyID))
+	})
+	m.HandleFunc("/GetProtectionKey", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodGet)
+		if got := r.URL.Query().Get("ProtectionKeyId"); got != "'"+protectionKeyID+"'" {
+			t.Errorf("unexpected ProtectionKeyId: %v", got)
+		}
+		json.NewEncoder(w).Encode(testWrapValue(base64.StdEncoding.EncodeToString(der)))
+	})
+	m.HandleFunc("/ContentKeys", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodPost)
+		testAMSHeader(t, r, false)
+
+		var params ContentKey
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(params.ID, "nb:kid:UUID:") {
+			t.Errorf("unexpected Id: %v", params.ID)
+		}
+		if params.ContentKeyType != ContentKeyTypeEnvelopeEncryption || params.ProtectionKeyID != protectionKeyID || params.ProtectionKeyType != ProtectionKeyTypeX509CertificateThumbprint {
+			t.Errorf("unexpected params: %#v", params)
+		}
+		encrypted, err := base64.StdEncoding.DecodeString(params.EncryptedContentKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decrypted, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, priv, encrypted, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(decrypted) != string(key) {
+			t.Errorf("unexpected content key. expected: %x, actual: %x", key, decrypted)
+		}
+		checksum, err := ContentKeyChecksum(params.ID, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if params.Checksum != checksum {
+			t.Errorf("unexpected checksum. expected: %v, actual: %v", checksum, params.Checksum)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(params)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	contentKey, err := client.CreateContentKeyWithValue(context.TODO(), "key", ContentKeyTypeEnvelopeEncryption, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentKey.Name != "key" {
+		t.Errorf("unexpected name: %v", contentKey.Name)
+	}
+
+	if _, err := client.CreateContentKeyWithValue(context.TODO(), "key", ContentKeyTypeEnvelopeEncryption, key[:8]); err == nil {
+		t.Error("expected error for short key")
+	}
+}
+
+func TestClient_LinkContentKey(t *testing.T) {
+	assetID := "nb:cid:UUID:asset"
+	contentKeyID := "nb:kid:UUID:key"
+
+	m := http.NewServeMux()
+	var linked, unlinked bool
+	m.HandleFunc("/Assets('nb:cid:UUID:asset')/$links/ContentKeys", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodPost)
+		testAMSHeader(t, r, false)
+
+		var params map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatal(err)
+		}
+		if uri, _ := url.PathUnescape(params["uri"]); !strings.HasSuffix(uri, "/ContentKeys('nb:kid:UUID:key')") {
+			t.Errorf("unexpected uri: %v", params["uri"])
+		}
+		linked = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+	m.HandleFunc("/Assets('nb:cid:UUID:asset')/$links/ContentKeys('nb:kid:UUID:key')",
+		func(w http.ResponseWriter, r *http.Request) {
+			testJSONHandler(t, http.MethodDelete, false, http.StatusNoContent, nil)(w, r)
+			unlinked = true
+		},
+	)
+	m.HandleFunc("/Assets('nb:cid:UUID:asset')/ContentKeys",
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue([]ContentKey{{ID: contentKeyID}})),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	if err := client.LinkContentKey(context.TODO(), assetID, contentKeyID); err != nil {
+		t.Error(err)
+	}
+	contentKeys, err := client.GetAssetContentKeys(context.TODO(), assetID)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(contentKeys) != 1 || contentKeys[0].ID != contentKeyID {
+		t.Errorf("unexpected content keys: %#v", contentKeys)
+	}
+	if err := client.UnlinkContentKey(context.TODO(), assetID, contentKeyID); err != nil {
+		t.Error(err)
+	}
+	if !linked || !unlinked {
+		t.Errorf("unexpected requests. linked: %v, unlinked: %v", linked, unlinked)
+	}
+}
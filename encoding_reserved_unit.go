@@ -0,0 +1,78 @@
+package ams
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/orisano/httpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	encodingReservedUnitTypesEndpoint = "EncodingReservedUnitTypes"
+)
+
+// ReservedUnitType decides the speed of the encoding reserved units.
+type ReservedUnitType int
+
+const (
+	ReservedUnitTypeBasic    ReservedUnitType = iota // S1
+	ReservedUnitTypeStandard                         // S2
+	ReservedUnitTypePremium                          // S3
+)
+
+// EncodingReservedUnitType is the type and the number of the encoding reserved units of the account.
+// The number of units is the number of tasks processed in parallel, and the type decides their speed.
+type EncodingReservedUnitType struct {
+	AccountID            string           `json:"AccountId"`
+	ReservedUnitType     ReservedUnitType `json:"ReservedUnitType"`
+	MaxReservableUnits   int              `json:"MaxReservableUnits"`
+	CurrentReservedUnits int              `json:"CurrentReservedUnits"`
+}
+
+func (c *Client) GetEncodingReservedUnitType(ctx context.Context) (*EncodingReservedUnitType, error) {
+	c.logger.Printf("[INFO] get encoding reserved unit type ...")
+
+	var out struct {
+		EncodingReservedUnitTypes []EncodingReservedUnitType `json:"value"`
+	}
+	if err := c.get(ctx, encodingReservedUnitTypesEndpoint, &out); err != nil {
+		return nil, err
+	}
+	if len(out.EncodingReservedUnitTypes) == 0 {
+		return nil, errors.New("encoding reserved unit type not found")
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return &out.EncodingReservedUnitTypes[0], nil
+}
+
+// UpdateEncodingReservedUnitType changes the type and the number of the encoding reserved units of the account.
+// accountID is EncodingReservedUnitType.AccountID.
+func (c *Client) UpdateEncodingReservedUnitType(ctx context.Context, accountID string, reservedUnitType ReservedUnitType, reservedUnits int) error {
+	switch reservedUnitType {
+	case ReservedUnitTypeBasic, ReservedUnitTypeStandard, ReservedUnitTypePremium:
+	default:
+		return errors.Errorf("unknown reserved unit type %d", reservedUnitType)
+	}
+	if reservedUnits < 0 {
+		return errors.New("reservedUnits must not be negative")
+	}
+
+	params := map[string]interface{}{
+		"ReservedUnitType":     reservedUnitType,
+		"CurrentReservedUnits": reservedUnits,
+	}
+	endpoint := fmt.Sprintf("%s(guid'%s')", encodingReservedUnitTypesEndpoint, accountID)
+	req, err := c.newRequest(ctx, "MERGE", endpoint, httpc.WithJSON(params))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] update encoding reserved unit type [type=%d,units=%d] ...", reservedUnitType, reservedUnits)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
@@ -0,0 +1,66 @@
+package ams
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_GetEncodingReservedUnitType(t *testing.T) {
+	expected := EncodingReservedUnitType{
+		AccountID:            "c4b8f3a2-0000-4000-8000-000000000000",
+		ReservedUnitType:     ReservedUnitTypeStandard,
+		MaxReservableUnits:   25,
+		CurrentReservedUnits: 2,
+	}
+	m := http.NewServeMux()
+	m.HandleFunc("/EncodingReservedUnitTypes",
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue([]EncodingReservedUnitType{expected})),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	actual, err := client.GetEncodingReservedUnitType(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*actual, expected) {
+		t.Errorf("unexpected encoding reserved unit type. expected: %#v, actual: %#v", expected, *actual)
+	}
+}
+
+func TestClient_UpdateEncodingReservedUnitType(t *testing.T) {
+	accountID := "c4b8f3a2-0000-4000-8000-000000000000"
+	m := http.NewServeMux()
+	m.HandleFunc("/EncodingReservedUnitTypes(guid'"+accountID+"')", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "MERGE")
+		testAMSHeader(t, r, false)
+
+		var params map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]interface{}{
+			"ReservedUnitType":     float64(ReservedUnitTypePremium),
+			"CurrentReservedUnits": float64(5),
+		}
+		if !reflect.DeepEqual(params, expected) {
+			t.Errorf("unexpected params. expected: %v, actual: %v", expected, params)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	if err := client.UpdateEncodingReservedUnitType(context.TODO(), accountID, ReservedUnitTypePremium, 5); err != nil {
+		t.Error(err)
+	}
+	if err := client.UpdateEncodingReservedUnitType(context.TODO(), accountID, 3, 5); err == nil {
+		t.Error("expected error for unknown reserved unit type")
+	}
+}
@@ -0,0 +1,160 @@
+package ams
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/orisano/httpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	filtersEndpoint = "Filters"
+)
+
+const (
+	FilterPropertyType     = "Type"
+	FilterPropertyName     = "Name"
+	FilterPropertyLanguage = "Language"
+	FilterPropertyFourCC   = "FourCC"
+	FilterPropertyBitrate  = "Bitrate"
+)
+
+const (
+	FilterOperatorEqual    = "Equal"
+	FilterOperatorNotEqual = "NotEqual"
+)
+
+const (
+	FilterTrackTypeVideo = "video"
+	FilterTrackTypeAudio = "audio"
+	FilterTrackTypeText  = "text"
+)
+
+// PresentationTimeRange values are expressed in Timescale units (default: 10000000, i.e. 100ns).
+type PresentationTimeRange struct {
+	StartTimestamp             int64 `json:"StartTimestamp,string,omitempty"`
+	EndTimestamp               int64 `json:"EndTimestamp,string,omitempty"`
+	PresentationWindowDuration int64 `json:"PresentationWindowDuration,string,omitempty"`
+	LiveBackoffDuration        int64 `json:"LiveBackoffDuration,string,omitempty"`
+	Timescale                  int64 `json:"Timescale,string,omitempty"`
+	ForceEndTimestamp          bool  `json:"ForceEndTimestamp,omitempty"`
+}
+
+type FilterTrackPropertyCondition struct {
+	Property string `json:"Property"`
+	Value    string `json:"Value"`
+	Operator string `json:"Operator"`
+}
+
+// FilterTrackSelect selects the tracks matching all of PropertyConditions.
+type FilterTrackSelect struct {
+	PropertyConditions []FilterTrackPropertyCondition `json:"PropertyConditions"`
+}
+
+func NewFilterTrackSelect(conditions ...FilterTrackPropertyCondition) FilterTrackSelect {
+	return FilterTrackSelect{
+		PropertyConditions: conditions,
+	}
+}
+
+func FilterTrackType(trackType string) FilterTrackPropertyCondition {
+	return FilterTrackPropertyCondition{
+		Property: FilterPropertyType,
+		Value:    trackType,
+		Operator: FilterOperatorEqual,
+	}
+}
+
+func FilterBitrateRange(min, max int) FilterTrackPropertyCondition {
+	return FilterTrackPropertyCondition{
+		Property: FilterPropertyBitrate,
+		Value:    fmt.Sprintf("%d-%d", min, max),
+		Operator: FilterOperatorEqual,
+	}
+}
+
+type Filter struct {
+	Name                  string                 `json:"Name"`
+	PresentationTimeRange *PresentationTimeRange `json:"PresentationTimeRange,omitempty"`
+	Tracks                []FilterTrackSelect    `json:"Tracks,omitempty"`
+}
+
+func (c *Client) CreateFilter(ctx context.Context, name string, presentationTimeRange *PresentationTimeRange, tracks []FilterTrackSelect) (*Filter, error) {
+	c.logger.Printf("[INFO] create filter [name=%#v] ...", name)
+
+	params := &Filter{
+		Name:                  name,
+		PresentationTimeRange: presentationTimeRange,
+		Tracks:                tracks,
+	}
+	var out Filter
+	if err := c.post(ctx, filtersEndpoint, params, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return &out, nil
+}
+
+func (c *Client) GetFilter(ctx context.Context, name string) (*Filter, error) {
+	c.logger.Printf("[INFO] get filter #%s ...", name)
+
+	endpoint := toFilterResource(name)
+	var out Filter
+	if err := c.get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return &out, nil
+}
+
+func (c *Client) GetFilters(ctx context.Context) ([]Filter, error) {
+	c.logger.Printf("[INFO] get filters ...")
+
+	var out struct {
+		Filters []Filter `json:"value"`
+	}
+	if err := c.get(ctx, filtersEndpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.Filters, nil
+}
+
+func (c *Client) UpdateFilter(ctx context.Context, filter *Filter) error {
+	endpoint := toFilterResource(filter.Name)
+	req, err := c.newRequest(ctx, "MERGE", endpoint, httpc.WithJSON(filter))
+	if err != nil {
+		return errors.Wrap(err, "request build failed")
+	}
+
+	c.logger.Printf("[INFO] update filter #%s ...", filter.Name)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+func (c *Client) DeleteFilter(ctx context.Context, name string) error {
+	endpoint := toFilterResource(name)
+	req, err := c.newRequest(ctx, http.MethodDelete, endpoint)
+	if err != nil {
+		return errors.Wrap(err, "request build failed")
+	}
+
+	c.logger.Printf("[INFO] delete filter #%s ...", name)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+func toFilterResource(name string) string {
+	return toResource(filtersEndpoint, name)
+}
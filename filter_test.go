@@ -0,0 +1,156 @@
+package ams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_CreateFilter(t *testing.T) {
+	name := "Mobile"
+	presentationTimeRange := &PresentationTimeRange{
+		StartTimestamp:             0,
+		EndTimestamp:               9223372036854775807,
+		PresentationWindowDuration: 12000000000,
+		Timescale:                  10000000,
+	}
+	tracks := []FilterTrackSelect{
+		NewFilterTrackSelect(
+			FilterTrackType(FilterTrackTypeVideo),
+			FilterBitrateRange(550000, 1350000),
+		),
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc("/Filters", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodPost)
+		testAMSHeader(t, r, false)
+
+		var params map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]interface{}{
+			"Name": name,
+			"PresentationTimeRange": map[string]interface{}{
+				"EndTimestamp":               "9223372036854775807",
+				"PresentationWindowDuration": "12000000000",
+				"Timescale":                  "10000000",
+			},
+			"Tracks": []interface{}{
+				map[string]interface{}{
+					"PropertyConditions": []interface{}{
+						map[string]interface{}{"Property": "Type", "Value": "video", "Operator": "Equal"},
+						map[string]interface{}{"Property": "Bitrate", "Value": "550000-1350000", "Operator": "Equal"},
+					},
+				},
+			},
+		}
+		if !reflect.DeepEqual(params, expected) {
+			t.Errorf("unexpected params. expected: %#v, actual: %#v", expected, params)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(params); err != nil {
+			t.Fatal(err)
+		}
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	filter, err := client.CreateFilter(context.TODO(), name, presentationTimeRange, tracks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Filter{
+		Name:                  name,
+		PresentationTimeRange: presentationTimeRange,
+		Tracks:                tracks,
+	}
+	if !reflect.DeepEqual(filter, expected) {
+		t.Errorf("unexpected filter. expected: %#v, actual: %#v", expected, filter)
+	}
+}
+
+func TestClient_GetFilters(t *testing.T) {
+	expected := []Filter{
+		{
+			Name: "Mobile",
+			Tracks: []FilterTrackSelect{
+				NewFilterTrackSelect(FilterBitrateRange(0, 1000000)),
+			},
+		},
+		{
+			Name:                  "FirstMinute",
+			PresentationTimeRange: &PresentationTimeRange{EndTimestamp: 600000000, Timescale: 10000000},
+		},
+	}
+	m := http.NewServeMux()
+	m.HandleFunc("/Filters",
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue(expected)),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	actual, err := client.GetFilters(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected filters. expected: %#v, actual: %#v", expected, actual)
+	}
+}
+
+func TestClient_UpdateFilter(t *testing.T) {
+	expected := Filter{
+		Name:                  "Mobile",
+		PresentationTimeRange: &PresentationTimeRange{LiveBackoffDuration: 20000000},
+	}
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/Filters('%v')", expected.Name), func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "MERGE")
+		testAMSHeader(t, r, false)
+
+		var actual Filter
+		if err := json.NewDecoder(r.Body).Decode(&actual); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("unexpected body. expected: %#v, actual: %#v", expected, actual)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	if err := client.UpdateFilter(context.TODO(), &expected); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_DeleteFilter(t *testing.T) {
+	name := "Mobile"
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/Filters('%v')", name),
+		testJSONHandler(t, http.MethodDelete, false, http.StatusNoContent, nil),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	if err := client.DeleteFilter(context.TODO(), name); err != nil {
+		t.Error(err)
+	}
+}
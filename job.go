@@ -2,18 +2,19 @@ package ams
 
 import (
 	"context"
-	"encoding/xml"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/orisano/httpc"
 	"github.com/pkg/errors"
 )
 
 const (
-	jobsEndpoint   = "Jobs"
-	jobInputAsset  = "JobInputAsset(0)"
-	jobOutputAsset = "JobOutputAsset(0)"
+	jobsEndpoint = "Jobs"
 )
 
 const (
@@ -45,6 +46,7 @@ func NewMediaAsset(uri string) MediaAsset {
 type Job struct {
 	ID              string  `json:"Id"`
 	Name            string  `json:"Name"`
+	Created         string  `json:"Created,omitempty"`
 	StartTime       string  `json:"StartTime"`
 	EndTime         string  `json:"EndTime"`
 	LastModified    string  `json:"LastModified"`
@@ -53,47 +55,86 @@ type Job struct {
 	State           int     `json:"State"`
 }
 
-func (c *Client) addJob(ctx context.Context, assetID, mediaProcessorID, configuration string, taskBody *TaskBody) (*Job, error) {
-	jobName := fmt.Sprintf("Job - Asset %s", assetID)
-	assetURI := c.buildAssetURI(assetID)
-	taskName := fmt.Sprintf("Task - Asset %s", assetID)
-	taskBodyXML, err := xml.Marshal(taskBody)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to xml.Marshal taskBody")
+type jobOptions struct {
+	Name     string
+	TaskName string
+	Priority int
+	Output   TaskOutput
+	TaskOpts []TaskBuilderOption
+}
+
+type JobOption func(*jobOptions)
+
+// SetJobName overrides the default job name "Job - Asset <assetID>".
+func SetJobName(name string) JobOption {
+	return func(options *jobOptions) {
+		options.Name = name
 	}
+}
 
-	params := map[string]interface{}{
-		"Name": jobName,
-		"InputMediaAssets": []MediaAsset{
-			NewMediaAsset(assetURI),
-		},
-		"Tasks": []Task{
-			{
-				Name:             taskName,
-				Configuration:    configuration,
-				MediaProcessorID: mediaProcessorID,
-				TaskBody:         string(taskBodyXML),
-			},
-		},
+// SetTaskName overrides the default task name "Task - Asset <assetID>".
+func SetTaskName(name string) JobOption {
+	return func(options *jobOptions) {
+		options.TaskName = name
 	}
-	var out struct {
-		Data Job `json:"d"`
+}
+
+func SetJobPriority(priority int) JobOption {
+	return func(options *jobOptions) {
+		options.Priority = priority
 	}
-	err = c.post(ctx, jobsEndpoint, params, &out,
-		httpc.SetHeaderField("Content-Type", "application/json;odata=verbose"),
-		httpc.SetHeaderField("Accept", "application/json;odata=verbose"),
-	)
-	return &out.Data, nil
 }
 
-func (c *Client) AddEncodeJob(ctx context.Context, assetID, mediaProcessorID, outputAssetName string) (*Job, error) {
-	configuration := "Adaptive Streaming"
+func SetOutputAssetName(name string) JobOption {
+	return func(options *jobOptions) {
+		options.Output.Name = name
+	}
+}
 
+// SetOutputAssetCreationOptions sets the creation options (Option*) of the output asset, e.g. OptionStorageEncrypted.
+func SetOutputAssetCreationOptions(creationOptions int) JobOption {
+	return func(options *jobOptions) {
+		options.Output.CreationOptions = creationOptions
+	}
+}
+
+// SetJobTaskOptions sets the Options (TaskOption*) of the task like SetTaskBuilderOptions.
+func SetJobTaskOptions(taskOptions TaskOption) JobOption {
+	return func(options *jobOptions) {
+		options.TaskOpts = append(options.TaskOpts, SetTaskBuilderOptions(taskOptions))
+	}
+}
+
+func (c *Client) addJob(ctx context.Context, assetID, mediaProcessorID, configuration string, opts []JobOption) (*Job, error) {
+	options := &jobOptions{
+		Name:     fmt.Sprintf("Job - Asset %s", assetID),
+		TaskName: fmt.Sprintf("Task - Asset %s", assetID),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	builder := NewJobBuilder(options.Name)
+	builder.SetPriority(options.Priority)
+	input := builder.AddInputAsset(assetID)
+	builder.AddTask(options.TaskName, mediaProcessorID, configuration, []JobAsset{input}, options.Output, options.TaskOpts...)
+	return c.SubmitJob(ctx, builder)
+}
+
+func (c *Client) AddEncodeJob(ctx context.Context, assetID, mediaProcessorID, outputAssetName string, opts ...JobOption) (*Job, error) {
+	return c.AddEncodeJobWithConfiguration(ctx, assetID, mediaProcessorID, PresetAdaptiveStreaming, outputAssetName, opts...)
+}
+
+// AddEncodeJobWithConfiguration encodes the asset with configuration,
+// which is either a system preset name (Preset*) or a custom preset built by PresetBuilder.
+// An empty outputAssetName keeps the name given by SetOutputAssetName, if any.
+func (c *Client) AddEncodeJobWithConfiguration(ctx context.Context, assetID, mediaProcessorID, configuration, outputAssetName string, opts ...JobOption) (*Job, error) {
 	c.logger.Printf("[INFO] post encode asset[#%s] job ...", assetID)
 
-	taskBody := newTaskBody()
-	taskBody.OutputAsset.Name = outputAssetName
-	job, err := c.addJob(ctx, assetID, mediaProcessorID, configuration, taskBody)
+	if len(outputAssetName) != 0 {
+		opts = append([]JobOption{SetOutputAssetName(outputAssetName)}, opts...)
+	}
+	job, err := c.addJob(ctx, assetID, mediaProcessorID, configuration, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -101,35 +142,32 @@ func (c *Client) AddEncodeJob(ctx context.Context, assetID, mediaProcessorID, ou
 	return job, nil
 }
 
-func (c *Client) AddThumbnailJob(ctx context.Context, assetID, mediaProcessorID string) (*Job, error) {
-	configuration := `{
-  "Version": 1.0,
-  "Codecs": [
-    {
-      "PngLayers": [
-        {
-          "Type": "PngLayer",
-          "Width": "100%",
-          "Height": "100%"
-        }
-      ],
-      "Start": "{Best}",
-      "Type": "PngImage"
-    }
-  ],
-  "Outputs": [
-    {
-      "FileName": "{Basename}_{Index}{Extension}",
-      "Format": {
-        "Type": "PngFormat"
-      }
-    }
-  ]
-}`
+// DefaultThumbnailPreset creates a full size PNG from the best frame of the video.
+func DefaultThumbnailPreset() *PresetBuilder {
+	preset, _ := (&ThumbnailConfiguration{}).Preset()
+	return preset
+}
+
+// AddThumbnailJob creates a full size PNG from the best frame of the video.
+func (c *Client) AddThumbnailJob(ctx context.Context, assetID, mediaProcessorID string, opts ...JobOption) (*Job, error) {
+	return c.AddThumbnailJobWithConfiguration(ctx, assetID, mediaProcessorID, &ThumbnailConfiguration{}, opts...)
+}
+
+func (c *Client) AddThumbnailJobWithConfiguration(ctx context.Context, assetID, mediaProcessorID string, config *ThumbnailConfiguration, opts ...JobOption) (*Job, error) {
+	if config == nil {
+		return nil, errors.New("missing config")
+	}
+	preset, err := config.Preset()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid thumbnail configuration")
+	}
+	configuration, err := preset.Build()
+	if err != nil {
+		return nil, err
+	}
 	c.logger.Printf("[INFO] post thumbnail create [#%s] job ...", assetID)
 
-	taskBody := newTaskBody()
-	job, err := c.addJob(ctx, assetID, mediaProcessorID, configuration, taskBody)
+	job, err := c.addJob(ctx, assetID, mediaProcessorID, configuration, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +175,21 @@ func (c *Client) AddThumbnailJob(ctx context.Context, assetID, mediaProcessorID
 	return job, nil
 }
 
+func (c *Client) GetInputMediaAssets(ctx context.Context, jobID string) ([]Asset, error) {
+	c.logger.Printf("[INFO] get job[#%s]'s input media assets ...", jobID)
+
+	endpoint := path.Join(toJobResource(jobID), "InputMediaAssets")
+	var out struct {
+		Assets []Asset `json:"value"`
+	}
+	if err := c.get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.Assets, nil
+}
+
 func (c *Client) GetOutputMediaAssets(ctx context.Context, jobID string) ([]Asset, error) {
 	c.logger.Printf("[INFO] get job[#%s]'s output media assets ...", jobID)
 
@@ -165,6 +218,158 @@ func (c *Client) GetJob(ctx context.Context, jobID string) (*Job, error) {
 	return &out, nil
 }
 
+type jobsOptions struct {
+	IDs           []string
+	Names         []string
+	States        []int
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+type JobsOption func(*jobsOptions)
+
+// SetJobIDs narrows GetJobs to the jobs with one of ids.
+func SetJobIDs(ids ...string) JobsOption {
+	return func(options *jobsOptions) {
+		options.IDs = ids
+	}
+}
+
+// SetJobNames narrows GetJobs to the jobs named one of names.
+func SetJobNames(names ...string) JobsOption {
+	return func(options *jobsOptions) {
+		options.Names = names
+	}
+}
+
+// SetJobStates narrows GetJobs to the jobs in one of states (Job*).
+func SetJobStates(states ...int) JobsOption {
+	return func(options *jobsOptions) {
+		options.States = states
+	}
+}
+
+// SetJobCreatedAfter narrows GetJobs to the jobs created at or after t.
+func SetJobCreatedAfter(t time.Time) JobsOption {
+	return func(options *jobsOptions) {
+		options.CreatedAfter = t
+	}
+}
+
+// SetJobCreatedBefore narrows GetJobs to the jobs created before t.
+func SetJobCreatedBefore(t time.Time) JobsOption {
+	return func(options *jobsOptions) {
+		options.CreatedBefore = t
+	}
+}
+
+func (o *jobsOptions) filter() string {
+	var conditions []string
+	if len(o.IDs) != 0 {
+		ids := make([]string, 0, len(o.IDs))
+		for _, id := range o.IDs {
+			ids = append(ids, fmt.Sprintf("Id eq '%s'", strings.Replace(id, "'", "''", -1)))
+		}
+		conditions = append(conditions, "("+strings.Join(ids, " or ")+")")
+	}
+	if len(o.Names) != 0 {
+		names := make([]string, 0, len(o.Names))
+		for _, name := range o.Names {
+			names = append(names, fmt.Sprintf("Name eq '%s'", strings.Replace(name, "'", "''", -1)))
+		}
+		conditions = append(conditions, "("+strings.Join(names, " or ")+")")
+	}
+	if len(o.States) != 0 {
+		states := make([]string, 0, len(o.States))
+		for _, state := range o.States {
+			states = append(states, fmt.Sprintf("State eq %d", state))
+		}
+		conditions = append(conditions, "("+strings.Join(states, " or ")+")")
+	}
+	if !o.CreatedAfter.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("Created ge datetime'%s'", formatTime(o.CreatedAfter)))
+	}
+	if !o.CreatedBefore.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("Created lt datetime'%s'", formatTime(o.CreatedBefore)))
+	}
+	return strings.Join(conditions, " and ")
+}
+
+// GetJobs returns every job which matches opts, requesting them page by page.
+func (c *Client) GetJobs(ctx context.Context, opts ...JobsOption) ([]Job, error) {
+	c.logger.Printf("[INFO] get jobs ...")
+
+	options := &jobsOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	var reqOpts []httpc.RequestOption
+	if filter := options.filter(); len(filter) != 0 {
+		reqOpts = append(reqOpts, httpc.AddQuery("$filter", filter))
+	}
+
+	var jobs []Job
+	err := c.getList(ctx, jobsEndpoint, func(value json.RawMessage) (int, error) {
+		var page []Job
+		if err := json.Unmarshal(value, &page); err != nil {
+			return 0, err
+		}
+		jobs = append(jobs, page...)
+		return len(page), nil
+	}, reqOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return jobs, nil
+}
+
+// CancelJob requests to cancel the job. The job moves to JobCanceling, then JobCanceled.
+func (c *Client) CancelJob(ctx context.Context, jobID string) error {
+	req, err := c.newRequest(ctx, http.MethodGet, "CancelJob",
+		httpc.AddQuery("jobid", fmt.Sprintf("'%s'", jobID)),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] cancel job #%s ...", jobID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+// DeleteJob deletes the job. A job which is not finished yet should be canceled first.
+func (c *Client) DeleteJob(ctx context.Context, jobID string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, toJobResource(jobID))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] delete job #%s ...", jobID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+func (c *Client) GetJobTasks(ctx context.Context, jobID string) ([]Task, error) {
+	c.logger.Printf("[INFO] get job[#%s]'s tasks ...", jobID)
+
+	endpoint := path.Join(toJobResource(jobID), tasksEndpoint)
+	var out struct {
+		Tasks []Task `json:"value"`
+	}
+	if err := c.get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.Tasks, nil
+}
+
 func toJobResource(jobID string) string {
 	return toResource(jobsEndpoint, jobID)
 }
@@ -0,0 +1,251 @@
+package ams
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+
+	"github.com/orisano/httpc"
+	"github.com/pkg/errors"
+)
+
+// JobAsset refers to an input asset of a job or an output asset of one of its tasks within the TaskBody.
+type JobAsset struct {
+	output bool
+	index  int
+}
+
+func (a JobAsset) String() string {
+	if a.output {
+		return fmt.Sprintf("JobOutputAsset(%d)", a.index)
+	}
+	return fmt.Sprintf("JobInputAsset(%d)", a.index)
+}
+
+// TaskOutput describes the output asset of a task.
+type TaskOutput struct {
+	Name string
+	// CreationOptions is a combination of Option* (e.g. OptionStorageEncrypted).
+	CreationOptions int
+}
+
+type jobBuilderTask struct {
+	name             string
+	mediaProcessorID string
+	configuration    string
+	inputs           []JobAsset
+	output           TaskOutput
+	options          TaskOption
+	priority         int
+}
+
+// TaskOption is the Options of a task.
+type TaskOption int
+
+const (
+	TaskOptionNone TaskOption = iota
+	// TaskOptionProtectedConfiguration tells AMS that the configuration is encrypted with the configuration key of the account.
+	// The encryption parameters of the task can't be set yet, so a job with this option is rejected on submit.
+	TaskOptionProtectedConfiguration
+)
+
+type TaskBuilderOption func(*jobBuilderTask)
+
+// SetTaskBuilderOptions sets the Options (TaskOption*) of the task.
+func SetTaskBuilderOptions(options TaskOption) TaskBuilderOption {
+	return func(task *jobBuilderTask) {
+		task.options = options
+	}
+}
+
+func SetTaskPriority(priority int) TaskBuilderOption {
+	return func(task *jobBuilderTask) {
+		task.priority = priority
+	}
+}
+
+// JobBuilder builds a job which consists of multiple input assets and tasks.
+// A task can consume the output asset of a preceding task.
+type JobBuilder struct {
+	name          string
+	priority      int
+	inputAssetIDs []string
+	tasks         []jobBuilderTask
+	subscriptions []JobNotificationSubscription
+}
+
+const (
+	TargetJobStateNone = iota
+	TargetJobStateFinalStatesOnly
+	TargetJobStateAll
+)
+
+type JobNotificationSubscription struct {
+	NotificationEndPointID string `json:"NotificationEndPointId"`
+	TargetJobState         int    `json:"TargetJobState"`
+}
+
+func NewJobBuilder(name string) *JobBuilder {
+	return &JobBuilder{
+		name: name,
+	}
+}
+
+// SetPriority sets the priority of the job. A job with higher priority is processed first.
+func (b *JobBuilder) SetPriority(priority int) {
+	b.priority = priority
+}
+
+// AddInputAsset adds assetID to the input assets of the job and returns its reference.
+func (b *JobBuilder) AddInputAsset(assetID string) JobAsset {
+	b.inputAssetIDs = append(b.inputAssetIDs, assetID)
+	return JobAsset{output: false, index: len(b.inputAssetIDs) - 1}
+}
+
+// AddTask adds a task which processes inputs and returns the reference of its output asset.
+func (b *JobBuilder) AddTask(name, mediaProcessorID, configuration string, inputs []JobAsset, output TaskOutput, opts ...TaskBuilderOption) JobAsset {
+	b.tasks = append(b.tasks, newJobBuilderTask(name, mediaProcessorID, configuration, inputs, output, opts))
+	return JobAsset{output: true, index: len(b.tasks) - 1}
+}
+
+// AddNotificationSubscription makes AMS notify the state changes of the job to the notification endpoint.
+// targetJobState is one of TargetJobState*.
+func (b *JobBuilder) AddNotificationSubscription(notificationEndPointID string, targetJobState int) {
+	b.subscriptions = append(b.subscriptions, JobNotificationSubscription{
+		NotificationEndPointID: notificationEndPointID,
+		TargetJobState:         targetJobState,
+	})
+}
+
+func (b *JobBuilder) build(c *Client) (map[string]interface{}, error) {
+	if len(b.name) == 0 {
+		return nil, errors.New("missing job name")
+	}
+	if len(b.inputAssetIDs) == 0 {
+		return nil, errors.New("missing input assets")
+	}
+	taskBodies, err := buildTaskBodies(b.tasks, len(b.inputAssetIDs))
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]Task, 0, len(b.tasks))
+	for i, task := range b.tasks {
+		switch task.options {
+		case TaskOptionNone:
+		case TaskOptionProtectedConfiguration:
+			return nil, errors.Errorf("TaskOptionProtectedConfiguration of task[%d] is not supported", i)
+		default:
+			return nil, errors.Errorf("unknown options %d of task[%d]", task.options, i)
+		}
+		taskBodyXML, err := xml.Marshal(taskBodies[i])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to xml.Marshal taskBody")
+		}
+		tasks = append(tasks, Task{
+			Name:             task.name,
+			Configuration:    task.configuration,
+			MediaProcessorID: task.mediaProcessorID,
+			TaskBody:         string(taskBodyXML),
+			Options:          int(task.options),
+			Priority:         task.priority,
+		})
+	}
+
+	for i, subscription := range b.subscriptions {
+		if len(subscription.NotificationEndPointID) == 0 {
+			return nil, errors.Errorf("missing NotificationEndPointID of subscription[%d]", i)
+		}
+	}
+
+	params := map[string]interface{}{
+		"Name":             b.name,
+		"InputMediaAssets": c.buildInputMediaAssets(b.inputAssetIDs),
+		"Tasks":            tasks,
+	}
+	if b.priority != 0 {
+		params["Priority"] = b.priority
+	}
+	if len(b.subscriptions) != 0 {
+		params["JobNotificationSubscriptions"] = b.subscriptions
+	}
+	return params, nil
+}
+
+func newJobBuilderTask(name, mediaProcessorID, configuration string, inputs []JobAsset, output TaskOutput, opts []TaskBuilderOption) jobBuilderTask {
+	task := jobBuilderTask{
+		name:             name,
+		mediaProcessorID: mediaProcessorID,
+		configuration:    configuration,
+		inputs:           inputs,
+		output:           output,
+	}
+	for _, opt := range opts {
+		opt(&task)
+	}
+	return task
+}
+
+// buildTaskBodies wires the tasks to the numInputs input assets and the outputs of the preceding tasks.
+func buildTaskBodies(tasks []jobBuilderTask, numInputs int) ([]*TaskBody, error) {
+	if len(tasks) == 0 {
+		return nil, errors.New("missing tasks")
+	}
+	taskBodies := make([]*TaskBody, 0, len(tasks))
+	for i, task := range tasks {
+		if len(task.inputs) == 0 {
+			return nil, errors.Errorf("missing inputs of task[%d]", i)
+		}
+		taskBody := &TaskBody{
+			OutputAssets: []AssetTag{
+				{
+					Asset:           JobAsset{output: true, index: i}.String(),
+					Name:            task.output.Name,
+					CreationOptions: task.output.CreationOptions,
+				},
+			},
+		}
+		for _, input := range task.inputs {
+			if input.output && input.index >= i {
+				return nil, errors.Errorf("task[%d] must not consume %v, which isn't produced by a preceding task", i, input)
+			}
+			if !input.output && input.index >= numInputs {
+				return nil, errors.Errorf("task[%d] consumes unknown %v", i, input)
+			}
+			taskBody.InputAssets = append(taskBody.InputAssets, AssetTag{Asset: input.String()})
+		}
+		taskBodies = append(taskBodies, taskBody)
+	}
+	return taskBodies, nil
+}
+
+func (c *Client) buildInputMediaAssets(assetIDs []string) []MediaAsset {
+	inputMediaAssets := make([]MediaAsset, 0, len(assetIDs))
+	for _, assetID := range assetIDs {
+		inputMediaAssets = append(inputMediaAssets, NewMediaAsset(c.buildAssetURI(assetID)))
+	}
+	return inputMediaAssets
+}
+
+func (c *Client) SubmitJob(ctx context.Context, builder *JobBuilder) (*Job, error) {
+	if builder == nil {
+		return nil, errors.New("missing builder")
+	}
+	params, err := builder.build(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid job")
+	}
+
+	c.logger.Printf("[INFO] submit job [name=%#v] ...", builder.name)
+	var out struct {
+		Data Job `json:"d"`
+	}
+	err = c.post(ctx, jobsEndpoint, params, &out,
+		httpc.SetHeaderField("Content-Type", "application/json;odata=verbose"),
+		httpc.SetHeaderField("Accept", "application/json;odata=verbose"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Printf("[INFO] completed, new job[#%s]", out.Data.ID)
+	return &out.Data, nil
+}
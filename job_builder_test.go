@@ -0,0 +1,130 @@
+package ams
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestJobAsset_String(t *testing.T) {
+	b := NewJobBuilder("sample-job")
+	input := b.AddInputAsset("sample-asset-id")
+	if expected := "JobInputAsset(0)"; input.String() != expected {
+		t.Errorf("unexpected reference. expected: %v, actual: %v", expected, input.String())
+	}
+	output := b.AddTask("sample-task", "sample-media-processor-id", "", []JobAsset{input}, TaskOutput{})
+	if expected := "JobOutputAsset(0)"; output.String() != expected {
+		t.Errorf("unexpected reference. expected: %v, actual: %v", expected, output.String())
+	}
+}
+
+func TestClient_SubmitJob(t *testing.T) {
+	assetID := "sample-asset-id"
+
+	var client *Client
+
+	m := http.NewServeMux()
+	m.HandleFunc("/Jobs", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodPost)
+		testAMSHeader(t, r, true)
+
+		actual := verifyJobRequest(t, r.Body)
+		if len(actual.InputMediaAssets) != 1 {
+			t.Fatalf("unexpected InputMediaAssets length. expected: 1, actual: %v", len(actual.InputMediaAssets))
+		}
+		if assetURI := client.buildAssetURI(assetID); actual.InputMediaAssets[0].MetaData.URI != assetURI {
+			t.Errorf("unexpected AssetURI. expected: %v, actual: %v", assetURI, actual.InputMediaAssets[0].MetaData.URI)
+		}
+
+		expectedTaskBodies := []string{
+			`<taskBody><inputAsset>JobInputAsset(0)</inputAsset><outputAsset assetName="encoded" assetCreationOptions="1">JobOutputAsset(0)</outputAsset></taskBody>`,
+			`<taskBody><inputAsset>JobOutputAsset(0)</inputAsset><outputAsset assetName="thumbnails">JobOutputAsset(1)</outputAsset></taskBody>`,
+			`<taskBody><inputAsset>JobInputAsset(0)</inputAsset><outputAsset>JobOutputAsset(2)</outputAsset></taskBody>`,
+		}
+		if len(actual.Tasks) != len(expectedTaskBodies) {
+			t.Fatalf("unexpected Tasks length. expected: %v, actual: %v", len(expectedTaskBodies), len(actual.Tasks))
+		}
+		for i, task := range actual.Tasks {
+			if task.TaskBody != expectedTaskBodies[i] {
+				t.Errorf("unexpected TaskBody of task[%d]. expected: %v, actual: %v", i, expectedTaskBodies[i], task.TaskBody)
+			}
+			var taskBody TaskBody
+			if err := xml.Unmarshal([]byte(task.TaskBody), &taskBody); err != nil {
+				t.Error(err)
+			}
+		}
+
+		expectedSubscriptions := []JobNotificationSubscription{
+			{NotificationEndPointID: "sample-endpoint-id", TargetJobState: TargetJobStateFinalStatesOnly},
+		}
+		if !reflect.DeepEqual(actual.JobNotificationSubscriptions, expectedSubscriptions) {
+			t.Errorf("unexpected JobNotificationSubscriptions. expected: %#v, actual: %#v", expectedSubscriptions, actual.JobNotificationSubscriptions)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"d":{"Id":"sample-job-id","Name":"sample-job","State":0}}`)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client = testClient(t, s.URL)
+
+	b := NewJobBuilder("sample-job")
+	input := b.AddInputAsset(assetID)
+	encoded := b.AddTask("encode", "encoder-id", "Adaptive Streaming", []JobAsset{input}, TaskOutput{Name: "encoded", CreationOptions: OptionStorageEncrypted})
+	b.AddTask("thumbnail", "encoder-id", "{}", []JobAsset{encoded}, TaskOutput{Name: "thumbnails"})
+	b.AddTask("analytics", "indexer-id", "", []JobAsset{input}, TaskOutput{})
+	b.AddNotificationSubscription("sample-endpoint-id", TargetJobStateFinalStatesOnly)
+
+	job, err := client.SubmitJob(context.TODO(), b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "sample-job-id" {
+		t.Errorf("unexpected ID. expected: %v, actual: %v", "sample-job-id", job.ID)
+	}
+}
+
+func TestClient_SubmitJob_invalid(t *testing.T) {
+	client := testClient(t, "http://dummy.url/api")
+
+	cases := map[string]func() *JobBuilder{
+		"missingInputAssets": func() *JobBuilder {
+			b := NewJobBuilder("sample-job")
+			b.AddTask("task", "mp-id", "", []JobAsset{{}}, TaskOutput{})
+			return b
+		},
+		"missingTasks": func() *JobBuilder {
+			b := NewJobBuilder("sample-job")
+			b.AddInputAsset("asset-id")
+			return b
+		},
+		"forwardReference": func() *JobBuilder {
+			b := NewJobBuilder("sample-job")
+			b.AddInputAsset("asset-id")
+			b.AddTask("task", "mp-id", "", []JobAsset{{output: true, index: 0}}, TaskOutput{})
+			return b
+		},
+		"unknownInput": func() *JobBuilder {
+			b := NewJobBuilder("sample-job")
+			b.AddInputAsset("asset-id")
+			b.AddTask("task", "mp-id", "", []JobAsset{{index: 1}}, TaskOutput{})
+			return b
+		},
+	}
+	for name, newBuilder := range cases {
+		t.Run(name, func(t *testing.T) {
+			job, err := client.SubmitJob(context.TODO(), newBuilder())
+			if err == nil {
+				t.Error("accept invalid job")
+			}
+			if job != nil {
+				t.Error("return invalid job")
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package ams
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"path"
+
+	"github.com/orisano/httpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	jobTemplatesEndpoint  = "JobTemplates"
+	taskTemplatesEndpoint = "TaskTemplates"
+	taskTemplateIDPrefix  = "nb:ttid:UUID:"
+)
+
+const (
+	TemplateTypeAccountLevel = iota
+	TemplateTypeSystemLevel
+)
+
+type JobTemplate struct {
+	ID                  string `json:"Id"`
+	Name                string `json:"Name"`
+	Created             string `json:"Created"`
+	LastModified        string `json:"LastModified"`
+	JobTemplateBody     string `json:"JobTemplateBody"`
+	NumberofInputAssets int    `json:"NumberofInputAssets"`
+	TemplateType        int    `json:"TemplateType"`
+}
+
+type TaskTemplate struct {
+	ID                   string `json:"Id"`
+	Name                 string `json:"Name"`
+	Configuration        string `json:"Configuration"`
+	MediaProcessorID     string `json:"MediaProcessorId"`
+	NumberofInputAssets  int    `json:"NumberofInputAssets"`
+	NumberofOutputAssets int    `json:"NumberofOutputAssets"`
+	Options              int    `json:"Options"`
+}
+
+type jobTemplateBody struct {
+	XMLName    xml.Name    `xml:"jobTemplate"`
+	TaskBodies []*TaskBody `xml:"taskBody"`
+}
+
+// JobTemplateBuilder builds a reusable multi-task workflow.
+// It is either instantiated locally by NewJob or stored in the account by Client.CreateJobTemplate.
+type JobTemplateBuilder struct {
+	name      string
+	numInputs int
+	tasks     []jobBuilderTask
+}
+
+func NewJobTemplateBuilder(name string, numberOfInputAssets int) *JobTemplateBuilder {
+	return &JobTemplateBuilder{
+		name:      name,
+		numInputs: numberOfInputAssets,
+	}
+}
+
+// InputAsset returns the reference of the i-th input asset, which is given when a job is submitted.
+func (b *JobTemplateBuilder) InputAsset(i int) JobAsset {
+	return JobAsset{output: false, index: i}
+}
+
+// AddTask adds a task which processes inputs and returns the reference of its output asset.
+func (b *JobTemplateBuilder) AddTask(name, mediaProcessorID, configuration string, inputs []JobAsset, output TaskOutput, opts ...TaskBuilderOption) JobAsset {
+	b.tasks = append(b.tasks, newJobBuilderTask(name, mediaProcessorID, configuration, inputs, output, opts))
+	return JobAsset{output: true, index: len(b.tasks) - 1}
+}
+
+// NewJob instantiates the template locally with assetIDs as its input assets.
+func (b *JobTemplateBuilder) NewJob(name string, assetIDs ...string) (*JobBuilder, error) {
+	if len(assetIDs) != b.numInputs {
+		return nil, errors.Errorf("template %#v requires %d input assets, but got %d", b.name, b.numInputs, len(assetIDs))
+	}
+	if _, err := buildTaskBodies(b.tasks, b.numInputs); err != nil {
+		return nil, errors.Wrap(err, "invalid template")
+	}
+	job := NewJobBuilder(name)
+	for _, assetID := range assetIDs {
+		job.AddInputAsset(assetID)
+	}
+	job.tasks = append(job.tasks, b.tasks...)
+	return job, nil
+}
+
+func (b *JobTemplateBuilder) build() (map[string]interface{}, error) {
+	if len(b.name) == 0 {
+		return nil, errors.New("missing template name")
+	}
+	if b.numInputs <= 0 {
+		return nil, errors.New("numberOfInputAssets must be greater than 0")
+	}
+	taskBodies, err := buildTaskBodies(b.tasks, b.numInputs)
+	if err != nil {
+		return nil, err
+	}
+
+	taskTemplates := make([]map[string]interface{}, 0, len(b.tasks))
+	for i, task := range b.tasks {
+		id, err := newUUID()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to generate task template id")
+		}
+		taskBodies[i].TaskTemplateID = taskTemplateIDPrefix + id
+		taskTemplates = append(taskTemplates, map[string]interface{}{
+			"Id":                   taskBodies[i].TaskTemplateID,
+			"Name":                 task.name,
+			"Configuration":        task.configuration,
+			"MediaProcessorId":     task.mediaProcessorID,
+			"NumberofInputAssets":  len(task.inputs),
+			"NumberofOutputAssets": 1,
+			"Options":              task.options,
+		})
+	}
+	body, err := xml.Marshal(&jobTemplateBody{TaskBodies: taskBodies})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to xml.Marshal jobTemplateBody")
+	}
+
+	return map[string]interface{}{
+		"Name":                b.name,
+		"JobTemplateBody":     xml.Header + string(body),
+		"NumberofInputAssets": b.numInputs,
+		"TemplateType":        TemplateTypeAccountLevel,
+		"TaskTemplates":       taskTemplates,
+	}, nil
+}
+
+// CreateJobTemplate stores the template in the account together with its task templates.
+func (c *Client) CreateJobTemplate(ctx context.Context, builder *JobTemplateBuilder) (*JobTemplate, error) {
+	if builder == nil {
+		return nil, errors.New("missing builder")
+	}
+	params, err := builder.build()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid job template")
+	}
+
+	c.logger.Printf("[INFO] create job template [name=%#v] ...", builder.name)
+	var out struct {
+		Data JobTemplate `json:"d"`
+	}
+	err = c.post(ctx, jobTemplatesEndpoint, params, &out,
+		httpc.SetHeaderField("Content-Type", "application/json;odata=verbose"),
+		httpc.SetHeaderField("Accept", "application/json;odata=verbose"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Printf("[INFO] completed, new job template[#%s]", out.Data.ID)
+	return &out.Data, nil
+}
+
+func (c *Client) GetJobTemplate(ctx context.Context, jobTemplateID string) (*JobTemplate, error) {
+	c.logger.Printf("[INFO] get job template #%s ...", jobTemplateID)
+
+	endpoint := toJobTemplateResource(jobTemplateID)
+	var out JobTemplate
+	if err := c.get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return &out, nil
+}
+
+func (c *Client) GetJobTemplates(ctx context.Context) ([]JobTemplate, error) {
+	c.logger.Printf("[INFO] get job templates ...")
+
+	var out struct {
+		JobTemplates []JobTemplate `json:"value"`
+	}
+	if err := c.get(ctx, jobTemplatesEndpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.JobTemplates, nil
+}
+
+// UpdateJobTemplate renames the job template. Its tasks can't be changed; create a new template instead.
+func (c *Client) UpdateJobTemplate(ctx context.Context, jobTemplateID, name string) error {
+	params := map[string]interface{}{
+		"Name": name,
+	}
+	endpoint := toJobTemplateResource(jobTemplateID)
+	req, err := c.newRequest(ctx, "MERGE", endpoint, httpc.WithJSON(params))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] update job template #%s ...", jobTemplateID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+// DeleteJobTemplate deletes the job template and its task templates.
+func (c *Client) DeleteJobTemplate(ctx context.Context, jobTemplateID string) error {
+	endpoint := toJobTemplateResource(jobTemplateID)
+	req, err := c.newRequest(ctx, http.MethodDelete, endpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] delete job template #%s ...", jobTemplateID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+func (c *Client) GetTaskTemplate(ctx context.Context, taskTemplateID string) (*TaskTemplate, error) {
+	c.logger.Printf("[INFO] get task template #%s ...", taskTemplateID)
+
+	endpoint := toResource(taskTemplatesEndpoint, taskTemplateID)
+	var out TaskTemplate
+	if err := c.get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return &out, nil
+}
+
+func (c *Client) GetTaskTemplates(ctx context.Context) ([]TaskTemplate, error) {
+	return c.getTaskTemplates(ctx, taskTemplatesEndpoint)
+}
+
+func (c *Client) GetJobTemplateTaskTemplates(ctx context.Context, jobTemplateID string) ([]TaskTemplate, error) {
+	endpoint := path.Join(toJobTemplateResource(jobTemplateID), taskTemplatesEndpoint)
+	return c.getTaskTemplates(ctx, endpoint)
+}
+
+func (c *Client) getTaskTemplates(ctx context.Context, endpoint string) ([]TaskTemplate, error) {
+	c.logger.Printf("[INFO] get task templates ...")
+
+	var out struct {
+		TaskTemplates []TaskTemplate `json:"value"`
+	}
+	if err := c.get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.TaskTemplates, nil
+}
+
+// SubmitJobFromTemplate submits a job from the account-level job template with assetIDs as its input assets.
+func (c *Client) SubmitJobFromTemplate(ctx context.Context, name, jobTemplateID string, assetIDs []string) (*Job, error) {
+	if len(name) == 0 {
+		return nil, errors.New("missing name")
+	}
+	if len(jobTemplateID) == 0 {
+		return nil, errors.New("missing jobTemplateID")
+	}
+	if len(assetIDs) == 0 {
+		return nil, errors.New("missing assetIDs")
+	}
+
+	params := map[string]interface{}{
+		"Name":             name,
+		"InputMediaAssets": c.buildInputMediaAssets(assetIDs),
+		"TemplateId":       jobTemplateID,
+	}
+
+	c.logger.Printf("[INFO] submit job [name=%#v] from template #%s ...", name, jobTemplateID)
+	var out struct {
+		Data Job `json:"d"`
+	}
+	err := c.post(ctx, jobsEndpoint, params, &out,
+		httpc.SetHeaderField("Content-Type", "application/json;odata=verbose"),
+		httpc.SetHeaderField("Accept", "application/json;odata=verbose"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Printf("[INFO] completed, new job[#%s]", out.Data.ID)
+	return &out.Data, nil
+}
+
+func toJobTemplateResource(jobTemplateID string) string {
+	return toResource(jobTemplatesEndpoint, jobTemplateID)
+}
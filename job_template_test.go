@@ -0,0 +1,167 @@
+package ams
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testJobTemplateBuilder() *JobTemplateBuilder {
+	b := NewJobTemplateBuilder("encode-and-thumbnail", 1)
+	encoded := b.AddTask("encode", "encoder-id", PresetAdaptiveStreaming, []JobAsset{b.InputAsset(0)}, TaskOutput{Name: "encoded"})
+	b.AddTask("thumbnail", "encoder-id", "{}", []JobAsset{encoded}, TaskOutput{})
+	return b
+}
+
+func TestClient_CreateJobTemplate(t *testing.T) {
+	m := http.NewServeMux()
+	m.HandleFunc("/JobTemplates", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodPost)
+		testAMSHeader(t, r, true)
+
+		var params struct {
+			Name                string
+			JobTemplateBody     string
+			NumberofInputAssets int
+			TaskTemplates       []TaskTemplate
+		}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatal(err)
+		}
+		if params.Name != "encode-and-thumbnail" || params.NumberofInputAssets != 1 {
+			t.Errorf("unexpected params: %#v", params)
+		}
+		if len(params.TaskTemplates) != 2 {
+			t.Fatalf("unexpected TaskTemplates length. expected: 2, actual: %v", len(params.TaskTemplates))
+		}
+		var body jobTemplateBody
+		if err := xml.Unmarshal([]byte(params.JobTemplateBody), &body); err != nil {
+			t.Fatal(err)
+		}
+		if len(body.TaskBodies) != 2 {
+			t.Fatalf("unexpected taskBody length. expected: 2, actual: %v", len(body.TaskBodies))
+		}
+		for i, taskTemplate := range params.TaskTemplates {
+			if !strings.HasPrefix(taskTemplate.ID, taskTemplateIDPrefix) {
+				t.Errorf("unexpected task template id: %v", taskTemplate.ID)
+			}
+			if body.TaskBodies[i].TaskTemplateID != taskTemplate.ID {
+				t.Errorf("taskBody[%d] must refer %v, but %v", i, taskTemplate.ID, body.TaskBodies[i].TaskTemplateID)
+			}
+		}
+		if actual := body.TaskBodies[1].InputAssets[0].Asset; actual != "JobOutputAsset(0)" {
+			t.Errorf("unexpected input of the second task: %v", actual)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"d":{"Id":"nb:jtid:UUID:sample-template-id","Name":"encode-and-thumbnail","NumberofInputAssets":1}}`)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	template, err := client.CreateJobTemplate(context.TODO(), testJobTemplateBuilder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.ID != "nb:jtid:UUID:sample-template-id" {
+		t.Errorf("unexpected ID: %v", template.ID)
+	}
+}
+
+func TestClient_SubmitJobFromTemplate(t *testing.T) {
+	templateID := "nb:jtid:UUID:sample-template-id"
+	assetID := "sample-asset-id"
+
+	var client *Client
+
+	m := http.NewServeMux()
+	m.HandleFunc("/Jobs", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodPost)
+		testAMSHeader(t, r, true)
+
+		var params struct {
+			Name             string
+			InputMediaAssets []MediaAsset
+			TemplateID       string `json:"TemplateId"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatal(err)
+		}
+		expected := []MediaAsset{NewMediaAsset(client.buildAssetURI(assetID))}
+		if !reflect.DeepEqual(params.InputMediaAssets, expected) {
+			t.Errorf("unexpected InputMediaAssets. expected: %#v, actual: %#v", expected, params.InputMediaAssets)
+		}
+		if params.TemplateID != templateID {
+			t.Errorf("unexpected TemplateId. expected: %v, actual: %v", templateID, params.TemplateID)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"d":{"Id":"sample-job-id","Name":"sample-job"}}`)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client = testClient(t, s.URL)
+	job, err := client.SubmitJobFromTemplate(context.TODO(), "sample-job", templateID, []string{assetID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "sample-job-id" {
+		t.Errorf("unexpected ID: %v", job.ID)
+	}
+}
+
+func TestJobTemplateBuilder_NewJob(t *testing.T) {
+	b := testJobTemplateBuilder()
+
+	if _, err := b.NewJob("sample-job", "asset-1", "asset-2"); err == nil {
+		t.Error("accept wrong number of input assets")
+	}
+
+	job, err := b.NewJob("sample-job", "asset-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(job.inputAssetIDs, []string{"asset-1"}) {
+		t.Errorf("unexpected input assets: %v", job.inputAssetIDs)
+	}
+	if len(job.tasks) != 2 {
+		t.Errorf("unexpected tasks length. expected: 2, actual: %v", len(job.tasks))
+	}
+}
+
+func TestClient_GetJobTemplateTaskTemplates(t *testing.T) {
+	templateID := "sample-template-id"
+	expected := []TaskTemplate{
+		{ID: "nb:ttid:UUID:sample-task-template-id", Name: "encode", Configuration: PresetAdaptiveStreaming, MediaProcessorID: "encoder-id", NumberofInputAssets: 1, NumberofOutputAssets: 1},
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/JobTemplates('%v')/TaskTemplates", templateID),
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue(expected)),
+	)
+	m.HandleFunc(fmt.Sprintf("/JobTemplates('%v')", templateID),
+		testJSONHandler(t, http.MethodDelete, false, http.StatusNoContent, nil),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	actual, err := client.GetJobTemplateTaskTemplates(context.TODO(), templateID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected task templates. expected: %#v, actual: %#v", expected, actual)
+	}
+	if err := client.DeleteJobTemplate(context.TODO(), templateID); err != nil {
+		t.Error(err)
+	}
+}
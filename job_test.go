@@ -8,14 +8,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 )
 
 type jobRequest struct {
-	Name             string
-	InputMediaAssets []MediaAsset
-	Tasks            []Task
+	Name                         string
+	Priority                     int
+	InputMediaAssets             []MediaAsset
+	Tasks                        []Task
+	JobNotificationSubscriptions []JobNotificationSubscription
 }
 
 func verifyJobRequest(t *testing.T, r io.Reader) *jobRequest {
@@ -77,6 +80,52 @@ func TestClient_AddEncodeJob(t *testing.T) {
 	}
 }
 
+func TestClient_AddEncodeJob_options(t *testing.T) {
+	m := http.NewServeMux()
+	m.HandleFunc("/Jobs", func(w http.ResponseWriter, r *http.Request) {
+		actual := verifyJobRequest(t, r.Body)
+		if actual.Name != "nightly encode" {
+			t.Errorf("unexpected Name. expected: %v, actual: %v", "nightly encode", actual.Name)
+		}
+		if actual.Priority != 10 {
+			t.Errorf("unexpected Priority. expected: %v, actual: %v", 10, actual.Priority)
+		}
+		task := actual.Tasks[0]
+		if task.Name != "encode" {
+			t.Errorf("unexpected task Name. expected: %v, actual: %v", "encode", task.Name)
+		}
+		if task.Options != int(TaskOptionNone) {
+			t.Errorf("unexpected task Options. expected: %v, actual: %v", TaskOptionNone, task.Options)
+		}
+		expected := `<taskBody><inputAsset>JobInputAsset(0)</inputAsset><outputAsset assetName="encoded" assetCreationOptions="1">JobOutputAsset(0)</outputAsset></taskBody>`
+		if task.TaskBody != expected {
+			t.Errorf("unexpected TaskBody. expected: %v, actual: %v", expected, task.TaskBody)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"d":{"Id":"sample-job-id"}}`)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	_, err := client.AddEncodeJob(context.TODO(), "sample-id", "sample-media-processor-id", "encoded",
+		SetJobName("nightly encode"),
+		SetTaskName("encode"),
+		SetJobPriority(10),
+		SetOutputAssetCreationOptions(OptionStorageEncrypted),
+		SetJobTaskOptions(TaskOptionNone),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, taskOptions := range []TaskOption{TaskOptionProtectedConfiguration, TaskOption(2)} {
+		if _, err := client.AddEncodeJob(context.TODO(), "sample-id", "sample-media-processor-id", "encoded", SetJobTaskOptions(taskOptions)); err == nil {
+			t.Errorf("expected error for task options %v", taskOptions)
+		}
+	}
+}
+
 func TestClient_AddThumbnailJob(t *testing.T) {
 	assetID := "sample-id"
 	mediaProcessorID := "sample-media-processor-id"
@@ -171,3 +220,197 @@ func TestClient_GetJob(t *testing.T) {
 		t.Errorf("unexpected job. expected: %#v, actual: %#v", expected, actual)
 	}
 }
+
+func TestClient_GetInputMediaAssets(t *testing.T) {
+	jobID := "sample-job-id"
+	expected := []Asset{
+		testAsset("source-asset-id", "Source"),
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/Jobs('%v')/InputMediaAssets", jobID),
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue(expected)),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	actual, err := client.GetInputMediaAssets(context.TODO(), jobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected input media assets. expected: %#v, actual: %#v", expected, actual)
+	}
+}
+
+func TestClient_GetJobs(t *testing.T) {
+	expected := []Job{
+		{
+			ID:           "sample-job-id-1",
+			Name:         "Sample Job 1",
+			LastModified: formatTime(time.Now()),
+			State:        JobFinished,
+		},
+		{
+			ID:           "sample-job-id-2",
+			Name:         "Sample Job 2",
+			LastModified: formatTime(time.Now()),
+			State:        JobProcessing,
+		},
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc("/Jobs",
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue(expected)),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	actual, err := client.GetJobs(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected jobs. expected: %#v, actual: %#v", expected, actual)
+	}
+}
+
+func TestClient_GetJobs_paged(t *testing.T) {
+	var expected []Job
+	for i := 0; i < listPageSize+1; i++ {
+		expected = append(expected, Job{ID: fmt.Sprintf("sample-job-id-%d", i)})
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc("/Jobs", func(w http.ResponseWriter, r *http.Request) {
+		skip, err := strconv.Atoi(r.URL.Query().Get("$skip"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if top := r.URL.Query().Get("$top"); top != strconv.Itoa(listPageSize) {
+			t.Errorf("unexpected $top: %v", top)
+		}
+		end := skip + listPageSize
+		if end > len(expected) {
+			end = len(expected)
+		}
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue(expected[skip:end]))(w, r)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	actual, err := client.GetJobs(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected jobs. expected: %d jobs, actual: %d jobs", len(expected), len(actual))
+	}
+}
+
+func TestClient_GetJobTasks(t *testing.T) {
+	jobID := "sample-job-id"
+	expected := []Task{
+		{
+			ID:               "sample-task-id",
+			Name:             "Sample Task",
+			Configuration:    "Adaptive Streaming",
+			MediaProcessorID: "sample-media-processor-id",
+			TaskBody:         `<taskBody><inputAsset>JobInputAsset(0)</inputAsset><outputAsset>JobOutputAsset(0)</outputAsset></taskBody>`,
+			State:            TaskCompleted,
+			Progress:         100,
+			StartTime:        "2017-08-10T02:52:53Z",
+			EndTime:          "2017-08-10T02:55:10Z",
+			RunningDuration:  137.2,
+			PerfMessage:      "Encode: 12.5x realtime",
+			ErrorDetails: []ErrorDetail{
+				{Code: "Warning", Message: "audio track not found"},
+			},
+			HistoricalEvents: []TaskHistoricalEvent{
+				{Code: "Scheduled", Message: "Task is scheduled", TimeStamp: "2017-08-10T02:52:50Z"},
+				{Code: "Processing", Message: "Task is processing", TimeStamp: "2017-08-10T02:52:53Z"},
+			},
+		},
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/Jobs('%v')/Tasks", jobID),
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue(expected)),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	actual, err := client.GetJobTasks(context.TODO(), jobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected tasks. expected: %#v, actual: %#v", expected, actual)
+	}
+}
+
+func TestClient_GetJobs_filter(t *testing.T) {
+	createdAfter := time.Date(2017, 8, 10, 0, 0, 0, 0, time.UTC)
+	createdBefore := createdAfter.Add(24 * time.Hour)
+
+	m := http.NewServeMux()
+	m.HandleFunc("/Jobs", func(w http.ResponseWriter, r *http.Request) {
+		expected := "(Name eq 'encode') and (State eq 4 or State eq 5) and Created ge datetime'2017-08-10T00:00:00Z' and Created lt datetime'2017-08-11T00:00:00Z'"
+		if actual := r.URL.Query().Get("$filter"); actual != expected {
+			t.Errorf("unexpected $filter. expected: %v, actual: %v", expected, actual)
+		}
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue([]Job{}))(w, r)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	_, err := client.GetJobs(context.TODO(),
+		SetJobNames("encode"),
+		SetJobStates(JobError, JobCanceled),
+		SetJobCreatedAfter(createdAfter),
+		SetJobCreatedBefore(createdBefore),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_CancelJob(t *testing.T) {
+	jobID := "nb:jid:UUID:sample-job-id"
+
+	m := http.NewServeMux()
+	m.HandleFunc("/CancelJob", func(w http.ResponseWriter, r *http.Request) {
+		if actual, expected := r.URL.Query().Get("jobid"), fmt.Sprintf("'%v'", jobID); actual != expected {
+			t.Errorf("unexpected jobid. expected: %v, actual: %v", expected, actual)
+		}
+		testJSONHandler(t, http.MethodGet, false, http.StatusNoContent, nil)(w, r)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	if err := client.CancelJob(context.TODO(), jobID); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_DeleteJob(t *testing.T) {
+	jobID := "delete-job-id"
+
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/Jobs('%v')", jobID),
+		testJSONHandler(t, http.MethodDelete, false, http.StatusNoContent, nil),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	if err := client.DeleteJob(context.TODO(), jobID); err != nil {
+		t.Error(err)
+	}
+}
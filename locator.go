@@ -2,17 +2,22 @@ package ams
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 	"time"
 
+	"github.com/orisano/httpc"
 	"github.com/pkg/errors"
 )
 
-const (
-	locatorsEndpoint = "Locators"
-)
+const locatorsEndpoint = "Locators"
+
+// LocatorIDPrefix is the prefix of locator ids. The streaming URL of a locator contains its id without it.
+const LocatorIDPrefix = "nb:lid:UUID:"
 
 const (
 	LocatorNone = iota
@@ -42,15 +47,53 @@ func (l *Locator) ToUploadURL(name string) (*url.URL, error) {
 	return uploadURL, nil
 }
 
-func (c *Client) CreateLocator(ctx context.Context, accessPolicyID, assetID string, startTime time.Time, locatorType int) (*Locator, error) {
+// ToManifestURL builds the Smooth Streaming manifest URL of manifestName (*.ism),
+// trimmed by the given global or asset filters.
+func (l *Locator) ToManifestURL(manifestName string, filters ...string) (*url.URL, error) {
+	return l.ToStreamingURL(manifestName, StreamingFormatSmooth, SetStreamingFilters(filters...))
+}
+
+type locatorOptions struct {
+	ID   string
+	Name string
+}
+
+type LocatorOption func(*locatorOptions)
+
+// SetLocatorID creates the locator with a caller-chosen id, which determines its URL.
+// A bare GUID is prefixed with "nb:lid:UUID:".
+func SetLocatorID(locatorID string) LocatorOption {
+	return func(options *locatorOptions) {
+		options.ID = locatorID
+	}
+}
+
+func SetLocatorName(name string) LocatorOption {
+	return func(options *locatorOptions) {
+		options.Name = name
+	}
+}
+
+func (c *Client) CreateLocator(ctx context.Context, accessPolicyID, assetID string, startTime time.Time, locatorType int, opts ...LocatorOption) (*Locator, error) {
 	c.logger.Printf("[INFO] create locator ...")
 
+	options := &locatorOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	params := map[string]interface{}{
 		"AccessPolicyId": accessPolicyID,
 		"AssetId":        assetID,
 		"StartTime":      formatTime(startTime),
 		"Type":           locatorType,
 	}
+	if len(options.ID) != 0 {
+		params["Id"] = toLocatorID(options.ID)
+	}
+	if len(options.Name) != 0 {
+		params["Name"] = options.Name
+	}
 	var out Locator
 	if err := c.post(ctx, locatorsEndpoint, params, &out); err != nil {
 		return nil, err
@@ -60,6 +103,28 @@ func (c *Client) CreateLocator(ctx context.Context, accessPolicyID, assetID stri
 	return &out, nil
 }
 
+// UpdateLocator changes the StartTime and ExpirationDateTime of the locator, keeping its URL.
+// A zero startTime leaves StartTime unchanged.
+func (c *Client) UpdateLocator(ctx context.Context, locatorID string, startTime, expirationDateTime time.Time) error {
+	params := map[string]interface{}{
+		"ExpirationDateTime": formatTime(expirationDateTime),
+	}
+	if !startTime.IsZero() {
+		params["StartTime"] = formatTime(startTime)
+	}
+	endpoint := toLocatorResource(locatorID)
+	req, err := c.newRequest(ctx, "MERGE", endpoint, httpc.WithJSON(params))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] update locator #%s ...", locatorID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
 func (c *Client) DeleteLocator(ctx context.Context, locatorID string) error {
 	endpoint := toLocatorResource(locatorID)
 	req, err := c.newRequest(ctx, http.MethodDelete, endpoint)
@@ -74,15 +139,22 @@ func (c *Client) DeleteLocator(ctx context.Context, locatorID string) error {
 	return nil
 }
 
-func (c *Client) getLocators(ctx context.Context, endpoint string) ([]Locator, error) {
-	var out struct {
-		Locators []Locator `json:"value"`
-	}
-	if err := c.get(ctx, endpoint, &out); err != nil {
+// getLocators requests the locators page by page.
+func (c *Client) getLocators(ctx context.Context, endpoint string, opts ...httpc.RequestOption) ([]Locator, error) {
+	var locators []Locator
+	err := c.getList(ctx, endpoint, func(value json.RawMessage) (int, error) {
+		var page []Locator
+		if err := json.Unmarshal(value, &page); err != nil {
+			return 0, err
+		}
+		locators = append(locators, page...)
+		return len(page), nil
+	}, opts...)
+	if err != nil {
 		return nil, err
 	}
 
-	return out.Locators, nil
+	return locators, nil
 }
 
 func (c *Client) GetLocators(ctx context.Context) ([]Locator, error) {
@@ -94,6 +166,34 @@ func (c *Client) GetLocatorsWithAsset(ctx context.Context, assetID string) ([]Lo
 	return c.getLocators(ctx, endpoint)
 }
 
+func (c *Client) GetLocatorsWithName(ctx context.Context, name string) ([]Locator, error) {
+	filter := fmt.Sprintf("Name eq '%s'", strings.Replace(name, "'", "''", -1))
+	return c.getLocators(ctx, locatorsEndpoint, httpc.AddQuery("$filter", filter))
+}
+
+// GetLocatorsWithAccessPolicy returns the locators which use the access policy.
+func (c *Client) GetLocatorsWithAccessPolicy(ctx context.Context, accessPolicyID string) ([]Locator, error) {
+	filter := fmt.Sprintf("AccessPolicyId eq '%s'", strings.Replace(accessPolicyID, "'", "''", -1))
+	return c.getLocators(ctx, locatorsEndpoint, httpc.AddQuery("$filter", filter))
+}
+
+// NewLocatorID generates a random locator id, which can be given to SetLocatorID
+// to know the streaming URL before the locator is created.
+func NewLocatorID() (string, error) {
+	id, err := newUUID()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate uuid")
+	}
+	return LocatorIDPrefix + id, nil
+}
+
+func toLocatorID(id string) string {
+	if strings.HasPrefix(id, LocatorIDPrefix) {
+		return id
+	}
+	return LocatorIDPrefix + id
+}
+
 func toLocatorResource(locatorID string) string {
 	return toResource(locatorsEndpoint, locatorID)
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"regexp"
 	"testing"
 	"time"
 )
@@ -42,6 +43,58 @@ func TestLocator_ToUploadURL(t *testing.T) {
 	})
 }
 
+func TestNewLocatorID(t *testing.T) {
+	pattern := regexp.MustCompile(`^nb:lid:UUID:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	id1, err := NewLocatorID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id2, err := NewLocatorID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pattern.MatchString(id1) || id1 == id2 {
+		t.Errorf("unexpected locator ids: %v, %v", id1, id2)
+	}
+}
+
+func TestLocator_ToManifestURL(t *testing.T) {
+	locator := Locator{
+		Path: "https://fake.streaming.url/sample-locator-id/",
+	}
+	tcs := []struct {
+		Name     string
+		Filters  []string
+		Expected string
+	}{
+		{
+			Name:     "withoutFilters",
+			Expected: "https://fake.streaming.url/sample-locator-id/video.ism/manifest",
+		},
+		{
+			Name:     "withFilter",
+			Filters:  []string{"Mobile"},
+			Expected: "https://fake.streaming.url/sample-locator-id/video.ism/manifest(filter=Mobile)",
+		},
+		{
+			Name:     "withFilters",
+			Filters:  []string{"Mobile", "FirstMinute"},
+			Expected: "https://fake.streaming.url/sample-locator-id/video.ism/manifest(filter=Mobile;FirstMinute)",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.Name, func(t *testing.T) {
+			u, err := locator.ToManifestURL("video.ism", tc.Filters...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := u.String(); actual != tc.Expected {
+				t.Errorf("unexpected ManifestURL. expected: %v, actual: %v", tc.Expected, actual)
+			}
+		})
+	}
+}
+
 func TestClient_CreateLocator(t *testing.T) {
 	accessPolicyID := "sample-access-policy-id"
 	assetID := "sample-asset-id"
@@ -110,6 +163,145 @@ func TestClient_CreateLocator(t *testing.T) {
 	}
 }
 
+func TestClient_UpdateLocator(t *testing.T) {
+	locatorID := "update-locator-id"
+	startTime := time.Now().Add(-5 * time.Minute)
+	expirationDateTime := time.Now().Add(24 * time.Hour)
+	withStartTime := false
+
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/Locators('%v')", locatorID), func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, "MERGE")
+		testAMSHeader(t, r, false)
+
+		var params map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatal(err)
+		}
+		if params["ExpirationDateTime"] != formatTime(expirationDateTime) {
+			t.Errorf("unexpected ExpirationDateTime. expected: %v, actual: %v", formatTime(expirationDateTime), params["ExpirationDateTime"])
+		}
+		if !withStartTime {
+			if _, ok := params["StartTime"]; ok {
+				t.Errorf("unexpected StartTime: %v", params["StartTime"])
+			}
+		} else if params["StartTime"] != formatTime(startTime) {
+			t.Errorf("unexpected StartTime. expected: %v, actual: %v", formatTime(startTime), params["StartTime"])
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	t.Run("withStartTime", func(t *testing.T) {
+		withStartTime = true
+		if err := client.UpdateLocator(context.TODO(), locatorID, startTime, expirationDateTime); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("withoutStartTime", func(t *testing.T) {
+		withStartTime = false
+		if err := client.UpdateLocator(context.TODO(), locatorID, time.Time{}, expirationDateTime); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestClient_CreateLocatorWithOptions(t *testing.T) {
+	tcs := []struct {
+		Name       string
+		LocatorID  string
+		ExpectedID string
+	}{
+		{Name: "withGUID", LocatorID: "8e5ee4a4-6d7d-4f1e-9a3c-2f8c6e1d0b11", ExpectedID: "nb:lid:UUID:8e5ee4a4-6d7d-4f1e-9a3c-2f8c6e1d0b11"},
+		{Name: "withPrefixedID", LocatorID: "nb:lid:UUID:8e5ee4a4-6d7d-4f1e-9a3c-2f8c6e1d0b11", ExpectedID: "nb:lid:UUID:8e5ee4a4-6d7d-4f1e-9a3c-2f8c6e1d0b11"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.Name, func(t *testing.T) {
+			locatorName := "sample-locator-name"
+
+			m := http.NewServeMux()
+			m.HandleFunc("/Locators", func(w http.ResponseWriter, r *http.Request) {
+				testRequestMethod(t, r, http.MethodPost)
+				testAMSHeader(t, r, false)
+
+				var locator Locator
+				if err := json.NewDecoder(r.Body).Decode(&locator); err != nil {
+					t.Fatal(err)
+				}
+				if locator.ID != tc.ExpectedID {
+					t.Errorf("unexpected Id. expected: %v, actual: %v", tc.ExpectedID, locator.ID)
+				}
+				if locator.Name != locatorName {
+					t.Errorf("unexpected Name. expected: %v, actual: %v", locatorName, locator.Name)
+				}
+
+				w.WriteHeader(http.StatusCreated)
+				if err := json.NewEncoder(w).Encode(locator); err != nil {
+					t.Fatal(err)
+				}
+			})
+			s := httptest.NewServer(m)
+			defer s.Close()
+
+			client := testClient(t, s.URL)
+
+			locator, err := client.CreateLocator(context.TODO(), "sample-access-policy-id", "sample-asset-id", time.Now(), LocatorOnDemandOrigin,
+				SetLocatorID(tc.LocatorID),
+				SetLocatorName(locatorName),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if locator.ID != tc.ExpectedID {
+				t.Errorf("unexpected locator id. expected: %v, actual: %v", tc.ExpectedID, locator.ID)
+			}
+		})
+	}
+}
+
+func TestClient_GetLocatorsWithName(t *testing.T) {
+	name := "publish's locator"
+	expected := []Locator{
+		{
+			ID:             "sample-locator-id",
+			Type:           LocatorOnDemandOrigin,
+			Path:           "https://fake.streaming.url/sample-locator-id/",
+			AccessPolicyID: "sample-access-policy-id",
+			AssetID:        "sample-asset-id",
+			Name:           name,
+		},
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc("/Locators", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodGet)
+		testAMSHeader(t, r, false)
+
+		if actual, expected := r.URL.Query().Get("$filter"), "Name eq 'publish''s locator'"; actual != expected {
+			t.Errorf("unexpected $filter. expected: %v, actual: %v", expected, actual)
+		}
+		if err := json.NewEncoder(w).Encode(testWrapValue(expected)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	actual, err := client.GetLocatorsWithName(context.TODO(), name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected locators. expected: %#v, actual: %#v", expected, actual)
+	}
+}
+
 func TestClient_DeleteLocator(t *testing.T) {
 	locatorID := "delete-locator-id"
 
@@ -2,10 +2,33 @@ package ams
 
 import (
 	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 const (
 	mediaProcessorsEndpoint = "MediaProcessors"
+
+	defaultMediaProcessorCacheTTL = time.Hour
+)
+
+// Well-known media processor names.
+const (
+	MediaProcessorMediaEncoderStandard        = "Media Encoder Standard"
+	MediaProcessorMediaEncoderPremiumWorkflow = "Media Encoder Premium Workflow"
+	MediaProcessorIndexer                     = "Azure Media Indexer"
+	MediaProcessorIndexer2Preview             = "Azure Media Indexer 2 Preview"
+	MediaProcessorFaceDetector                = "Azure Media Face Detector"
+	MediaProcessorMotionDetector              = "Azure Media Motion Detector"
+	MediaProcessorVideoThumbnails             = "Azure Media Video Thumbnails"
+	MediaProcessorOCR                         = "Azure Media OCR"
+	MediaProcessorContentModerator            = "Azure Media Content Moderator"
+	MediaProcessorRedactor                    = "Azure Media Redactor"
+	MediaProcessorStorageDecryption           = "Storage Decryption"
 )
 
 type MediaProcessor struct {
@@ -30,3 +53,88 @@ func (c *Client) GetMediaProcessors(ctx context.Context) ([]MediaProcessor, erro
 	c.logger.Printf("[INFO] completed")
 	return out.MediaProcessors, nil
 }
+
+type mediaProcessorCache struct {
+	mu              sync.Mutex
+	ttl             time.Duration
+	mediaProcessors []MediaProcessor
+	expiresAt       time.Time
+}
+
+func (c *Client) getCachedMediaProcessors(ctx context.Context) ([]MediaProcessor, error) {
+	cache := c.mediaProcessors
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.mediaProcessors != nil && time.Now().Before(cache.expiresAt) {
+		return cache.mediaProcessors, nil
+	}
+	mediaProcessors, err := c.GetMediaProcessors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if cache.ttl > 0 {
+		cache.mediaProcessors = mediaProcessors
+		cache.expiresAt = time.Now().Add(cache.ttl)
+	}
+	return mediaProcessors, nil
+}
+
+// ResolveMediaProcessor finds the media processor by name (e.g. MediaProcessorMediaEncoderStandard).
+// An empty version picks the highest version, otherwise the version is pinned.
+// The media processors are cached on the client for SetMediaProcessorCacheTTL.
+func (c *Client) ResolveMediaProcessor(ctx context.Context, name, version string) (*MediaProcessor, error) {
+	if len(name) == 0 {
+		return nil, errors.New("missing name")
+	}
+	mediaProcessors, err := c.getCachedMediaProcessors(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get media processors")
+	}
+
+	var found *MediaProcessor
+	for i, mediaProcessor := range mediaProcessors {
+		if mediaProcessor.Name != name {
+			continue
+		}
+		if len(version) != 0 {
+			if mediaProcessor.Version == version {
+				found = &mediaProcessors[i]
+				break
+			}
+			continue
+		}
+		if found == nil || compareVersion(mediaProcessor.Version, found.Version) > 0 {
+			found = &mediaProcessors[i]
+		}
+	}
+	if found == nil {
+		if len(version) != 0 {
+			return nil, errors.Errorf("media processor not found. name='%v', version='%v'", name, version)
+		}
+		return nil, errors.Errorf("media processor not found. name='%v'", name)
+	}
+	mediaProcessor := *found
+	return &mediaProcessor, nil
+}
+
+// compareVersion compares dot-separated numeric versions such as "4.10.2".
+func compareVersion(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -55,3 +55,67 @@ func TestClient_GetMediaProcessors(t *testing.T) {
 		t.Errorf("unexpected media processors. expected: %#v, actual: %#v", expected, actual)
 	}
 }
+
+func TestClient_ResolveMediaProcessor(t *testing.T) {
+	mediaProcessors := []MediaProcessor{
+		{ID: "mes-4.9", Name: MediaProcessorMediaEncoderStandard, Version: "4.9"},
+		{ID: "mes-4.10", Name: MediaProcessorMediaEncoderStandard, Version: "4.10"},
+		{ID: "mes-4.2", Name: MediaProcessorMediaEncoderStandard, Version: "4.2"},
+		{ID: "indexer-1.2", Name: MediaProcessorIndexer, Version: "1.2"},
+	}
+	requests := 0
+	m := http.NewServeMux()
+	m.HandleFunc("/MediaProcessors", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue(mediaProcessors))(w, r)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	cases := []struct {
+		Name       string
+		Version    string
+		ExpectedID string
+	}{
+		{MediaProcessorMediaEncoderStandard, "", "mes-4.10"},
+		{MediaProcessorMediaEncoderStandard, "4.2", "mes-4.2"},
+		{MediaProcessorIndexer, "", "indexer-1.2"},
+	}
+	for _, tc := range cases {
+		mediaProcessor, err := client.ResolveMediaProcessor(context.TODO(), tc.Name, tc.Version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mediaProcessor.ID != tc.ExpectedID {
+			t.Errorf("unexpected media processor. name: %v, version: %v, expected: %v, actual: %v", tc.Name, tc.Version, tc.ExpectedID, mediaProcessor.ID)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("media processors must be cached. expected requests: 1, actual: %v", requests)
+	}
+
+	if _, err := client.ResolveMediaProcessor(context.TODO(), MediaProcessorMediaEncoderStandard, "5.0"); err == nil {
+		t.Error("accept unknown version")
+	}
+	if _, err := client.ResolveMediaProcessor(context.TODO(), MediaProcessorOCR, ""); err == nil {
+		t.Error("accept unknown name")
+	}
+
+	t.Run("withoutCache", func(t *testing.T) {
+		requests = 0
+		client, err := NewClient(s.URL, testAuthorizedClient(), SetMediaProcessorCacheTTL(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := client.ResolveMediaProcessor(context.TODO(), MediaProcessorMediaEncoderStandard, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if requests != 2 {
+			t.Errorf("unexpected requests. expected: 2, actual: %v", requests)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package ams
+
+import (
+	"encoding/xml"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AssetMetadata is the content of the metadata files generated by Media Encoder Standard.
+// <source>_metadata.xml describes the input files of the job, and <source>_manifest.xml describes the encoded files.
+type AssetMetadata struct {
+	XMLName    xml.Name            `xml:"AssetFiles"`
+	AssetFiles []AssetFileMetadata `xml:"AssetFile"`
+}
+
+type AssetFileMetadata struct {
+	Name            string               `xml:"Name,attr"`
+	Size            int64                `xml:"Size,attr"`
+	Duration        string               `xml:"Duration,attr"`
+	NumberOfStreams int                  `xml:"NumberOfStreams,attr"`
+	FormatNames     string               `xml:"FormatNames,attr"`
+	StartTime       string               `xml:"StartTime,attr"`
+	OverallBitRate  int                  `xml:"OverallBitRate,attr"`
+	Sources         []SourceMetadata     `xml:"Sources>Source"`
+	VideoTracks     []VideoTrackMetadata `xml:"VideoTracks>VideoTrack"`
+	AudioTracks     []AudioTrackMetadata `xml:"AudioTracks>AudioTrack"`
+}
+
+// DurationTime parses Duration, which is formatted as ISO 8601 duration (e.g. PT1M5.016S).
+func (m *AssetFileMetadata) DurationTime() (time.Duration, error) {
+	return parseISO8601Duration(m.Duration)
+}
+
+type SourceMetadata struct {
+	Name string `xml:"Name,attr"`
+}
+
+type VideoTrackMetadata struct {
+	ID                            int     `xml:"Id,attr"`
+	FourCC                        string  `xml:"FourCC,attr"`
+	Codec                         string  `xml:"Codec,attr"`
+	Profile                       string  `xml:"Profile,attr"`
+	Level                         string  `xml:"Level,attr"`
+	Width                         int     `xml:"Width,attr"`
+	Height                        int     `xml:"Height,attr"`
+	DisplayAspectRatioNumerator   float64 `xml:"DisplayAspectRatioNumerator,attr"`
+	DisplayAspectRatioDenominator float64 `xml:"DisplayAspectRatioDenominator,attr"`
+	FrameRate                     float64 `xml:"FrameRate,attr"`
+	// Bitrate, TargetBitrate and MaxGOPBitrate are in kbps for output assets.
+	Bitrate       int `xml:"Bitrate,attr"`
+	TargetBitrate int `xml:"TargetBitrate,attr"`
+	MaxGOPBitrate int `xml:"MaxGOPBitrate,attr"`
+}
+
+type AudioTrackMetadata struct {
+	ID            int    `xml:"Id,attr"`
+	Codec         string `xml:"Codec,attr"`
+	Language      string `xml:"Language,attr"`
+	Channels      int    `xml:"Channels,attr"`
+	ChannelLayout string `xml:"ChannelLayout,attr"`
+	SamplingRate  int    `xml:"SamplingRate,attr"`
+	SampleFormat  string `xml:"SampleFormat,attr"`
+	Bitrate       int    `xml:"Bitrate,attr"`
+	BitsPerSample int    `xml:"BitsPerSample,attr"`
+}
+
+func ParseAssetMetadata(r io.Reader) (*AssetMetadata, error) {
+	if r == nil {
+		return nil, errors.New("missing r")
+	}
+	var metadata AssetMetadata
+	if err := xml.NewDecoder(r).Decode(&metadata); err != nil {
+		return nil, errors.Wrap(err, "failed to decode metadata")
+	}
+	return &metadata, nil
+}
+
+var iso8601DurationPattern = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+func parseISO8601Duration(s string) (time.Duration, error) {
+	m := iso8601DurationPattern.FindStringSubmatch(s)
+	if m == nil || s == "P" || s == "PT" {
+		return 0, errors.Errorf("invalid ISO 8601 duration '%v'", s)
+	}
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if len(m[i+1]) == 0 {
+			continue
+		}
+		v, err := strconv.ParseFloat(m[i+1], 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid ISO 8601 duration '%v'", s)
+		}
+		d += time.Duration(math.Round(v * float64(unit)))
+	}
+	return d, nil
+}
@@ -0,0 +1,98 @@
+package ams
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAssetMetadata(t *testing.T) {
+	raw := `<?xml version="1.0" encoding="utf-8"?>
+<AssetFiles xmlns="http://schemas.microsoft.com/windowsazure/mediaservices/2014/07/mediaencoder/outputmetadata">
+  <AssetFile Name="small_1280x720_3400.mp4" Size="2250396" Duration="PT5.016S">
+    <Sources>
+      <Source Name="small.mp4" />
+    </Sources>
+    <VideoTracks>
+      <VideoTrack Id="1" FourCC="avc1" Profile="High" Level="3.1" DisplayAspectRatioNumerator="16" DisplayAspectRatioDenominator="9" Width="1280" Height="720" TargetBitrate="3400" MaxGOPBitrate="3587" FrameRate="29.97" />
+    </VideoTracks>
+    <AudioTracks>
+      <AudioTrack Id="2" Codec="AacLc" Language="und" Channels="2" SamplingRate="48000" Bitrate="128" BitsPerSample="16" />
+    </AudioTracks>
+  </AssetFile>
+</AssetFiles>`
+
+	metadata, err := ParseAssetMetadata(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []AssetFileMetadata{
+		{
+			Name:     "small_1280x720_3400.mp4",
+			Size:     2250396,
+			Duration: "PT5.016S",
+			Sources:  []SourceMetadata{{Name: "small.mp4"}},
+			VideoTracks: []VideoTrackMetadata{
+				{
+					ID:                            1,
+					FourCC:                        "avc1",
+					Profile:                       "High",
+					Level:                         "3.1",
+					Width:                         1280,
+					Height:                        720,
+					DisplayAspectRatioNumerator:   16,
+					DisplayAspectRatioDenominator: 9,
+					FrameRate:                     29.97,
+					TargetBitrate:                 3400,
+					MaxGOPBitrate:                 3587,
+				},
+			},
+			AudioTracks: []AudioTrackMetadata{
+				{
+					ID:            2,
+					Codec:         "AacLc",
+					Language:      "und",
+					Channels:      2,
+					SamplingRate:  48000,
+					Bitrate:       128,
+					BitsPerSample: 16,
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(metadata.AssetFiles, expected) {
+		t.Errorf("unexpected asset files. expected: %#v, actual: %#v", expected, metadata.AssetFiles)
+	}
+
+	d, err := metadata.AssetFiles[0].DurationTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := 5016 * time.Millisecond; d != expected {
+		t.Errorf("unexpected duration. expected: %v, actual: %v", expected, d)
+	}
+}
+
+func TestParseISO8601Duration(t *testing.T) {
+	tcs := []struct {
+		Input    string
+		Expected time.Duration
+		Valid    bool
+	}{
+		{Input: "PT5.016S", Expected: 5016 * time.Millisecond, Valid: true},
+		{Input: "PT1H2M3S", Expected: time.Hour + 2*time.Minute + 3*time.Second, Valid: true},
+		{Input: "P1DT1M", Expected: 24*time.Hour + time.Minute, Valid: true},
+		{Input: "PT", Valid: false},
+		{Input: "5S", Valid: false},
+	}
+	for _, tc := range tcs {
+		actual, err := parseISO8601Duration(tc.Input)
+		if tc.Valid != (err == nil) {
+			t.Errorf("unexpected result %v: %v", tc.Input, err)
+		}
+		if actual != tc.Expected {
+			t.Errorf("unexpected duration %v. expected: %v, actual: %v", tc.Input, tc.Expected, actual)
+		}
+	}
+}
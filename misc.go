@@ -1,6 +1,7 @@
 package ams
 
 import (
+	"crypto/rand"
 	"fmt"
 	"time"
 )
@@ -12,3 +13,13 @@ func toResource(name, id string) string {
 func formatTime(t time.Time) string {
 	return t.UTC().Format(time.RFC3339)
 }
+
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
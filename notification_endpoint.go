@@ -0,0 +1,144 @@
+package ams
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/orisano/httpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	notificationEndPointsEndpoint = "NotificationEndPoints"
+)
+
+const (
+	NotificationEndPointTypeNone       = 0
+	NotificationEndPointTypeAzureQueue = 1
+	NotificationEndPointTypeWebHook    = 3
+)
+
+const (
+	CredentialTypeNone         = 0
+	CredentialTypeDigestBase64 = 1
+)
+
+type NotificationEndPoint struct {
+	ID                          string `json:"Id"`
+	Name                        string `json:"Name"`
+	Created                     string `json:"Created"`
+	EndPointAddress             string `json:"EndPointAddress"`
+	EndPointType                int    `json:"EndPointType"`
+	CredentialType              int    `json:"CredentialType"`
+	EncryptedEndPointCredential string `json:"EncryptedEndPointCredential"`
+	ProtectionKeyID             string `json:"ProtectionKeyId"`
+	ProtectionKeyType           string `json:"ProtectionKeyType"`
+}
+
+type notificationEndPointOptions struct {
+	ProtectionKeyID             string
+	EncryptedEndPointCredential string
+}
+
+type NotificationEndPointOption func(*notificationEndPointOptions)
+
+// SetEndPointCredential sets the webhook signing key, encrypted with the X.509 protection key protectionKeyID
+// and base64 encoded. AMS signs every notification with the key, which WebhookHandler verifies.
+func SetEndPointCredential(protectionKeyID, encryptedEndPointCredential string) NotificationEndPointOption {
+	return func(options *notificationEndPointOptions) {
+		options.ProtectionKeyID = protectionKeyID
+		options.EncryptedEndPointCredential = encryptedEndPointCredential
+	}
+}
+
+func (c *Client) CreateNotificationEndPoint(ctx context.Context, name string, endPointType int, endPointAddress string, opts ...NotificationEndPointOption) (*NotificationEndPoint, error) {
+	c.logger.Printf("[INFO] create notification endpoint [name=%#v,address=%#v] ...", name, endPointAddress)
+
+	options := &notificationEndPointOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	params := map[string]interface{}{
+		"Name":            name,
+		"EndPointType":    endPointType,
+		"EndPointAddress": endPointAddress,
+	}
+	if len(options.EncryptedEndPointCredential) != 0 {
+		params["CredentialType"] = CredentialTypeDigestBase64
+		params["ProtectionKeyId"] = options.ProtectionKeyID
+		params["ProtectionKeyType"] = "X509CertificateThumbprint"
+		params["EncryptedEndPointCredential"] = options.EncryptedEndPointCredential
+	}
+	var out NotificationEndPoint
+	if err := c.post(ctx, notificationEndPointsEndpoint, params, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed, new notification endpoint[#%s]", out.ID)
+	return &out, nil
+}
+
+func (c *Client) GetNotificationEndPoint(ctx context.Context, notificationEndPointID string) (*NotificationEndPoint, error) {
+	c.logger.Printf("[INFO] get notification endpoint #%s ...", notificationEndPointID)
+
+	endpoint := toNotificationEndPointResource(notificationEndPointID)
+	var out NotificationEndPoint
+	if err := c.get(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return &out, nil
+}
+
+func (c *Client) GetNotificationEndPoints(ctx context.Context) ([]NotificationEndPoint, error) {
+	c.logger.Printf("[INFO] get notification endpoints ...")
+
+	var out struct {
+		NotificationEndPoints []NotificationEndPoint `json:"value"`
+	}
+	if err := c.get(ctx, notificationEndPointsEndpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.NotificationEndPoints, nil
+}
+
+// UpdateNotificationEndPoint changes the name and the address of the notification endpoint.
+func (c *Client) UpdateNotificationEndPoint(ctx context.Context, notificationEndPointID, name, endPointAddress string) error {
+	params := map[string]interface{}{
+		"Name":            name,
+		"EndPointAddress": endPointAddress,
+	}
+	endpoint := toNotificationEndPointResource(notificationEndPointID)
+	req, err := c.newRequest(ctx, "MERGE", endpoint, httpc.WithJSON(params))
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] update notification endpoint #%s ...", notificationEndPointID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+func (c *Client) DeleteNotificationEndPoint(ctx context.Context, notificationEndPointID string) error {
+	endpoint := toNotificationEndPointResource(notificationEndPointID)
+	req, err := c.newRequest(ctx, http.MethodDelete, endpoint)
+	if err != nil {
+		return errors.Wrap(err, "failed to construct request")
+	}
+	c.logger.Printf("[INFO] delete notification endpoint #%s ...", notificationEndPointID)
+	if err := c.do(req, http.StatusNoContent, nil); err != nil {
+		return errors.Wrap(err, "failed to request")
+	}
+	c.logger.Printf("[INFO] completed")
+	return nil
+}
+
+func toNotificationEndPointResource(notificationEndPointID string) string {
+	return toResource(notificationEndPointsEndpoint, notificationEndPointID)
+}
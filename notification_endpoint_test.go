@@ -0,0 +1,124 @@
+package ams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_CreateNotificationEndPoint(t *testing.T) {
+	expected := &NotificationEndPoint{
+		ID:                          "nb:nepid:UUID:sample-endpoint-id",
+		Name:                        "sample-webhook",
+		EndPointAddress:             "https://fake.url/webhook",
+		EndPointType:                NotificationEndPointTypeWebHook,
+		CredentialType:              CredentialTypeDigestBase64,
+		EncryptedEndPointCredential: "ZW5jcnlwdGVk",
+		ProtectionKeyID:             "sample-protection-key-id",
+		ProtectionKeyType:           "X509CertificateThumbprint",
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc("/NotificationEndPoints", func(w http.ResponseWriter, r *http.Request) {
+		var params map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatal(err)
+		}
+		for key, value := range map[string]interface{}{
+			"Name":                        expected.Name,
+			"EndPointAddress":             expected.EndPointAddress,
+			"EndPointType":                float64(expected.EndPointType),
+			"CredentialType":              float64(expected.CredentialType),
+			"ProtectionKeyId":             expected.ProtectionKeyID,
+			"EncryptedEndPointCredential": expected.EncryptedEndPointCredential,
+		} {
+			if params[key] != value {
+				t.Errorf("unexpected %v. expected: %v, actual: %v", key, value, params[key])
+			}
+		}
+		testJSONHandler(t, http.MethodPost, false, http.StatusCreated, expected)(w, r)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	actual, err := client.CreateNotificationEndPoint(context.TODO(), expected.Name, NotificationEndPointTypeWebHook, expected.EndPointAddress,
+		SetEndPointCredential(expected.ProtectionKeyID, expected.EncryptedEndPointCredential),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected notification endpoint. expected: %#v, actual: %#v", expected, actual)
+	}
+}
+
+func TestClient_GetNotificationEndPoints(t *testing.T) {
+	expected := []NotificationEndPoint{
+		{ID: "sample-endpoint-id-1", Name: "queue", EndPointAddress: "sample-queue", EndPointType: NotificationEndPointTypeAzureQueue},
+		{ID: "sample-endpoint-id-2", Name: "webhook", EndPointAddress: "https://fake.url/webhook", EndPointType: NotificationEndPointTypeWebHook},
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc("/NotificationEndPoints",
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, testWrapValue(expected)),
+	)
+	m.HandleFunc(fmt.Sprintf("/NotificationEndPoints('%v')", expected[0].ID),
+		testJSONHandler(t, http.MethodGet, false, http.StatusOK, expected[0]),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	actual, err := client.GetNotificationEndPoints(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected notification endpoints. expected: %#v, actual: %#v", expected, actual)
+	}
+
+	endPoint, err := client.GetNotificationEndPoint(context.TODO(), expected[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*endPoint, expected[0]) {
+		t.Errorf("unexpected notification endpoint. expected: %#v, actual: %#v", expected[0], *endPoint)
+	}
+}
+
+func TestClient_UpdateNotificationEndPoint(t *testing.T) {
+	endPointID := "sample-endpoint-id"
+
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/NotificationEndPoints('%v')", endPointID),
+		testJSONHandler(t, "MERGE", false, http.StatusNoContent, nil),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	if err := client.UpdateNotificationEndPoint(context.TODO(), endPointID, "renamed", "https://fake.url/new-webhook"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_DeleteNotificationEndPoint(t *testing.T) {
+	endPointID := "sample-endpoint-id"
+
+	m := http.NewServeMux()
+	m.HandleFunc(fmt.Sprintf("/NotificationEndPoints('%v')", endPointID),
+		testJSONHandler(t, http.MethodDelete, false, http.StatusNoContent, nil),
+	)
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+	if err := client.DeleteNotificationEndPoint(context.TODO(), endPointID); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,492 @@
+package ams
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Media Encoder Standard system presets.
+// ref: https://docs.microsoft.com/en-us/azure/media-services/previous/media-services-mes-presets-overview
+const (
+	PresetAdaptiveStreaming                        = "Adaptive Streaming"
+	PresetContentAdaptiveMultipleBitrateMP4        = "Content Adaptive Multiple Bitrate MP4"
+	PresetH264MultipleBitrate1080pAudio51          = "H264 Multiple Bitrate 1080p Audio 5.1"
+	PresetH264MultipleBitrate1080p                 = "H264 Multiple Bitrate 1080p"
+	PresetH264MultipleBitrate16x9ForIOS            = "H264 Multiple Bitrate 16x9 for iOS"
+	PresetH264MultipleBitrate16x9SDAudio51         = "H264 Multiple Bitrate 16x9 SD Audio 5.1"
+	PresetH264MultipleBitrate16x9SD                = "H264 Multiple Bitrate 16x9 SD"
+	PresetH264MultipleBitrate4KAudio51             = "H264 Multiple Bitrate 4K Audio 5.1"
+	PresetH264MultipleBitrate4K                    = "H264 Multiple Bitrate 4K"
+	PresetH264MultipleBitrate4x3ForIOS             = "H264 Multiple Bitrate 4x3 for iOS"
+	PresetH264MultipleBitrate4x3SDAudio51          = "H264 Multiple Bitrate 4x3 SD Audio 5.1"
+	PresetH264MultipleBitrate4x3SD                 = "H264 Multiple Bitrate 4x3 SD"
+	PresetH264MultipleBitrate720pAudio51           = "H264 Multiple Bitrate 720p Audio 5.1"
+	PresetH264MultipleBitrate720p                  = "H264 Multiple Bitrate 720p"
+	PresetH264SingleBitrate1080pAudio51            = "H264 Single Bitrate 1080p Audio 5.1"
+	PresetH264SingleBitrate1080p                   = "H264 Single Bitrate 1080p"
+	PresetH264SingleBitrate4KAudio51               = "H264 Single Bitrate 4K Audio 5.1"
+	PresetH264SingleBitrate4K                      = "H264 Single Bitrate 4K"
+	PresetH264SingleBitrate4x3SDAudio51            = "H264 Single Bitrate 4x3 SD Audio 5.1"
+	PresetH264SingleBitrate4x3SD                   = "H264 Single Bitrate 4x3 SD"
+	PresetH264SingleBitrate16x9SDAudio51           = "H264 Single Bitrate 16x9 SD Audio 5.1"
+	PresetH264SingleBitrate16x9SD                  = "H264 Single Bitrate 16x9 SD"
+	PresetH264SingleBitrate720pAudio51             = "H264 Single Bitrate 720p Audio 5.1"
+	PresetH264SingleBitrate720pForAndroid          = "H264 Single Bitrate 720p for Android"
+	PresetH264SingleBitrate720p                    = "H264 Single Bitrate 720p"
+	PresetH264SingleBitrateHighQualitySDForAndroid = "H264 Single Bitrate High Quality SD for Android"
+	PresetH264SingleBitrateLowQualitySDForAndroid  = "H264 Single Bitrate Low Quality SD for Android"
+	PresetAACGoodQualityAudio                      = "AAC Good Quality Audio"
+)
+
+// SystemPresets lists every Media Encoder Standard system preset name.
+var SystemPresets = []string{
+	PresetAdaptiveStreaming,
+	PresetContentAdaptiveMultipleBitrateMP4,
+	PresetH264MultipleBitrate1080pAudio51,
+	PresetH264MultipleBitrate1080p,
+	PresetH264MultipleBitrate16x9ForIOS,
+	PresetH264MultipleBitrate16x9SDAudio51,
+	PresetH264MultipleBitrate16x9SD,
+	PresetH264MultipleBitrate4KAudio51,
+	PresetH264MultipleBitrate4K,
+	PresetH264MultipleBitrate4x3ForIOS,
+	PresetH264MultipleBitrate4x3SDAudio51,
+	PresetH264MultipleBitrate4x3SD,
+	PresetH264MultipleBitrate720pAudio51,
+	PresetH264MultipleBitrate720p,
+	PresetH264SingleBitrate1080pAudio51,
+	PresetH264SingleBitrate1080p,
+	PresetH264SingleBitrate4KAudio51,
+	PresetH264SingleBitrate4K,
+	PresetH264SingleBitrate4x3SDAudio51,
+	PresetH264SingleBitrate4x3SD,
+	PresetH264SingleBitrate16x9SDAudio51,
+	PresetH264SingleBitrate16x9SD,
+	PresetH264SingleBitrate720pAudio51,
+	PresetH264SingleBitrate720pForAndroid,
+	PresetH264SingleBitrate720p,
+	PresetH264SingleBitrateHighQualitySDForAndroid,
+	PresetH264SingleBitrateLowQualitySDForAndroid,
+	PresetAACGoodQualityAudio,
+}
+
+// IsSystemPreset reports whether name is a Media Encoder Standard system preset.
+func IsSystemPreset(name string) bool {
+	for _, preset := range SystemPresets {
+		if preset == name {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	H264ProfileAuto     = "Auto"
+	H264ProfileBaseline = "Baseline"
+	H264ProfileMain     = "Main"
+	H264ProfileHigh     = "High"
+)
+
+const (
+	AACProfileLC              = "AACLC"
+	AACProfileHEV1            = "HEAACV1"
+	AACProfileHEV2            = "HEAACV2"
+	AACConditionInsertSilence = "InsertSilenceIfNoAudio"
+)
+
+const (
+	StretchModeNone     = "None"
+	StretchModeAutoSize = "AutoSize"
+	StretchModeAutoFit  = "AutoFit"
+)
+
+const (
+	OutputFormatMP4 = "MP4Format"
+	OutputFormatPng = "PngFormat"
+	OutputFormatJpg = "JpgFormat"
+	OutputFormatBmp = "BmpFormat"
+)
+
+// Codec is one of H264Video, AACAudio, PngImage, JpgImage and BmpImage.
+type Codec interface {
+	codecType() string
+	validate() error
+}
+
+type H264Layer struct {
+	Profile         string `json:"Profile,omitempty"`
+	Level           string `json:"Level,omitempty"`
+	Bitrate         int    `json:"Bitrate"`
+	MaxBitrate      int    `json:"MaxBitrate,omitempty"`
+	BufferWindow    string `json:"BufferWindow,omitempty"`
+	Width           string `json:"Width,omitempty"`
+	Height          string `json:"Height,omitempty"`
+	BFrames         int    `json:"BFrames,omitempty"`
+	ReferenceFrames int    `json:"ReferenceFrames,omitempty"`
+	AdaptiveBFrame  bool   `json:"AdaptiveBFrame,omitempty"`
+	Slices          int    `json:"Slices,omitempty"`
+	FrameRate       string `json:"FrameRate,omitempty"`
+	Label           string `json:"Label,omitempty"`
+}
+
+func (l H264Layer) MarshalJSON() ([]byte, error) {
+	type alias H264Layer
+	return json.Marshal(struct {
+		Type string `json:"Type"`
+		alias
+	}{"H264Layer", alias(l)})
+}
+
+type H264Video struct {
+	KeyFrameInterval     string      `json:"KeyFrameInterval,omitempty"`
+	SceneChangeDetection bool        `json:"SceneChangeDetection,omitempty"`
+	StretchMode          string      `json:"StretchMode,omitempty"`
+	H264Layers           []H264Layer `json:"H264Layers"`
+}
+
+func (v *H264Video) codecType() string {
+	return "H264Video"
+}
+
+func (v *H264Video) validate() error {
+	if len(v.H264Layers) == 0 {
+		return errors.New("missing H264Layers")
+	}
+	for i, layer := range v.H264Layers {
+		switch layer.Profile {
+		case "", H264ProfileAuto, H264ProfileBaseline, H264ProfileMain, H264ProfileHigh:
+		default:
+			return errors.Errorf("H264Layers[%d]: unknown profile '%v'", i, layer.Profile)
+		}
+		if layer.Bitrate <= 0 {
+			return errors.Errorf("H264Layers[%d]: Bitrate must be greater than 0", i)
+		}
+		if layer.MaxBitrate != 0 && layer.MaxBitrate < layer.Bitrate {
+			return errors.Errorf("H264Layers[%d]: MaxBitrate must not be less than Bitrate", i)
+		}
+		if err := validateDimension(layer.Width); err != nil {
+			return errors.Wrapf(err, "H264Layers[%d]: invalid Width", i)
+		}
+		if err := validateDimension(layer.Height); err != nil {
+			return errors.Wrapf(err, "H264Layers[%d]: invalid Height", i)
+		}
+	}
+	return nil
+}
+
+func (v *H264Video) MarshalJSON() ([]byte, error) {
+	type alias H264Video
+	return marshalCodec(v, (*alias)(v))
+}
+
+type AACAudio struct {
+	Profile      string `json:"Profile,omitempty"`
+	Channels     int    `json:"Channels,omitempty"`
+	SamplingRate int    `json:"SamplingRate,omitempty"`
+	Bitrate      int    `json:"Bitrate,omitempty"`
+	Condition    string `json:"Condition,omitempty"`
+	Label        string `json:"Label,omitempty"`
+}
+
+func (a *AACAudio) codecType() string {
+	return "AACAudio"
+}
+
+func (a *AACAudio) validate() error {
+	switch a.Profile {
+	case "", AACProfileLC, AACProfileHEV1, AACProfileHEV2:
+	default:
+		return errors.Errorf("unknown profile '%v'", a.Profile)
+	}
+	switch a.Channels {
+	case 0, 1, 2, 6:
+	default:
+		return errors.Errorf("unsupported Channels %v", a.Channels)
+	}
+	switch a.SamplingRate {
+	case 0, 8000, 11025, 12000, 16000, 22050, 24000, 32000, 44100, 48000, 88200, 96000:
+	default:
+		return errors.Errorf("unsupported SamplingRate %v", a.SamplingRate)
+	}
+	if a.Bitrate < 0 {
+		return errors.New("Bitrate must not be negative")
+	}
+	return nil
+}
+
+func (a *AACAudio) MarshalJSON() ([]byte, error) {
+	type alias AACAudio
+	return marshalCodec(a, (*alias)(a))
+}
+
+type ImageLayer struct {
+	Width  string `json:"Width,omitempty"`
+	Height string `json:"Height,omitempty"`
+	// Quality is used by JpgImage only (0-100).
+	Quality int `json:"Quality,omitempty"`
+}
+
+// typedImageLayer adds the layer type of the codec, e.g. "PngLayer", to an ImageLayer.
+type typedImageLayer struct {
+	Type string `json:"Type"`
+	ImageLayer
+}
+
+func typeImageLayers(layerType string, layers []ImageLayer) []typedImageLayer {
+	typed := make([]typedImageLayer, 0, len(layers))
+	for _, layer := range layers {
+		typed = append(typed, typedImageLayer{Type: layerType, ImageLayer: layer})
+	}
+	return typed
+}
+
+// ImageCodec holds the fields shared by PngImage, JpgImage and BmpImage.
+// Start, Step and Range accept "{Best}", a timestamp ("00:00:05"), a percentage ("10%") or a frame count ("30").
+type ImageCodec struct {
+	Start       string `json:"Start"`
+	Step        string `json:"Step,omitempty"`
+	Range       string `json:"Range,omitempty"`
+	StretchMode string `json:"StretchMode,omitempty"`
+	Label       string `json:"Label,omitempty"`
+}
+
+func (c *ImageCodec) validate(layers []ImageLayer) error {
+	if len(c.Start) == 0 {
+		return errors.New("missing Start")
+	}
+	if c.Start != "{Best}" && !imagePositionPattern.MatchString(c.Start) {
+		return errors.Errorf("invalid Start '%v'", c.Start)
+	}
+	if len(c.Step) != 0 && !imagePositionPattern.MatchString(c.Step) {
+		return errors.Errorf("invalid Step '%v'", c.Step)
+	}
+	if len(c.Range) != 0 && !imagePositionPattern.MatchString(c.Range) {
+		return errors.Errorf("invalid Range '%v'", c.Range)
+	}
+	if len(layers) == 0 {
+		return errors.New("missing layers")
+	}
+	for i, layer := range layers {
+		if err := validateDimension(layer.Width); err != nil {
+			return errors.Wrapf(err, "layers[%d]: invalid Width", i)
+		}
+		if err := validateDimension(layer.Height); err != nil {
+			return errors.Wrapf(err, "layers[%d]: invalid Height", i)
+		}
+		if layer.Quality < 0 || layer.Quality > 100 {
+			return errors.Errorf("layers[%d]: Quality must be between 0 and 100", i)
+		}
+	}
+	return nil
+}
+
+type PngImage struct {
+	ImageCodec
+	PngLayers []ImageLayer `json:"PngLayers"`
+}
+
+func (p *PngImage) codecType() string {
+	return "PngImage"
+}
+
+func (p *PngImage) validate() error {
+	return p.ImageCodec.validate(p.PngLayers)
+}
+
+func (p *PngImage) MarshalJSON() ([]byte, error) {
+	type alias PngImage
+	return marshalCodec(p, struct {
+		*alias
+		PngLayers []typedImageLayer `json:"PngLayers"`
+	}{(*alias)(p), typeImageLayers("PngLayer", p.PngLayers)})
+}
+
+type JpgImage struct {
+	ImageCodec
+	// SpriteColumn combines the images into a sprite sheet with the number of columns.
+	SpriteColumn int          `json:"SpriteColumn,omitempty"`
+	JpgLayers    []ImageLayer `json:"JpgLayers"`
+}
+
+func (j *JpgImage) codecType() string {
+	return "JpgImage"
+}
+
+func (j *JpgImage) validate() error {
+	if j.SpriteColumn < 0 {
+		return errors.New("SpriteColumn must not be negative")
+	}
+	return j.ImageCodec.validate(j.JpgLayers)
+}
+
+func (j *JpgImage) MarshalJSON() ([]byte, error) {
+	type alias JpgImage
+	return marshalCodec(j, struct {
+		*alias
+		JpgLayers []typedImageLayer `json:"JpgLayers"`
+	}{(*alias)(j), typeImageLayers("JpgLayer", j.JpgLayers)})
+}
+
+type BmpImage struct {
+	ImageCodec
+	BmpLayers []ImageLayer `json:"BmpLayers"`
+}
+
+func (b *BmpImage) codecType() string {
+	return "BmpImage"
+}
+
+func (b *BmpImage) validate() error {
+	return b.ImageCodec.validate(b.BmpLayers)
+}
+
+func (b *BmpImage) MarshalJSON() ([]byte, error) {
+	type alias BmpImage
+	return marshalCodec(b, struct {
+		*alias
+		BmpLayers []typedImageLayer `json:"BmpLayers"`
+	}{(*alias)(b), typeImageLayers("BmpLayer", b.BmpLayers)})
+}
+
+type PresetFormat struct {
+	Type string `json:"Type"`
+}
+
+type PresetOutput struct {
+	FileName string       `json:"FileName"`
+	Format   PresetFormat `json:"Format"`
+}
+
+// PresetSource trims the input. StartTime and Duration are timestamps such as "00:00:04".
+type PresetSource struct {
+	StartTime string `json:"StartTime,omitempty"`
+	Duration  string `json:"Duration,omitempty"`
+}
+
+// Preset is a custom Media Encoder Standard preset.
+// ref: https://docs.microsoft.com/en-us/azure/media-services/previous/media-services-mes-schema
+type Preset struct {
+	Version float64        `json:"Version"`
+	Sources []PresetSource `json:"Sources,omitempty"`
+	Codecs  []Codec        `json:"Codecs"`
+	Outputs []PresetOutput `json:"Outputs"`
+}
+
+// Validate checks the preset locally, so that a broken preset fails before the job is submitted.
+func (p *Preset) Validate() error {
+	if p.Version != 1.0 {
+		return errors.Errorf("unsupported Version %v", p.Version)
+	}
+	for i, source := range p.Sources {
+		if len(source.StartTime) != 0 && !timestampPattern.MatchString(source.StartTime) {
+			return errors.Errorf("Sources[%d]: invalid StartTime '%v'", i, source.StartTime)
+		}
+		if len(source.Duration) != 0 && !timestampPattern.MatchString(source.Duration) {
+			return errors.Errorf("Sources[%d]: invalid Duration '%v'", i, source.Duration)
+		}
+	}
+	if len(p.Codecs) == 0 {
+		return errors.New("missing Codecs")
+	}
+	codecTypes := make(map[string]bool)
+	for i, codec := range p.Codecs {
+		if codec == nil {
+			return errors.Errorf("Codecs[%d]: missing codec", i)
+		}
+		if err := codec.validate(); err != nil {
+			return errors.Wrapf(err, "Codecs[%d]: invalid %s", i, codec.codecType())
+		}
+		codecTypes[codec.codecType()] = true
+	}
+	if len(p.Outputs) == 0 {
+		return errors.New("missing Outputs")
+	}
+	for i, output := range p.Outputs {
+		if len(output.FileName) == 0 {
+			return errors.Errorf("Outputs[%d]: missing FileName", i)
+		}
+		var ok bool
+		switch output.Format.Type {
+		case OutputFormatMP4:
+			ok = codecTypes["H264Video"] || codecTypes["AACAudio"]
+		case OutputFormatPng:
+			ok = codecTypes["PngImage"]
+		case OutputFormatJpg:
+			ok = codecTypes["JpgImage"]
+		case OutputFormatBmp:
+			ok = codecTypes["BmpImage"]
+		default:
+			return errors.Errorf("Outputs[%d]: unknown format '%v'", i, output.Format.Type)
+		}
+		if !ok {
+			return errors.Errorf("Outputs[%d]: no codec produces %v", i, output.Format.Type)
+		}
+	}
+	return nil
+}
+
+// PresetBuilder builds a custom preset, which is validated on Build.
+type PresetBuilder struct {
+	preset Preset
+}
+
+func NewPresetBuilder() *PresetBuilder {
+	return &PresetBuilder{
+		preset: Preset{Version: 1.0},
+	}
+}
+
+func (b *PresetBuilder) AddSource(startTime, duration string) *PresetBuilder {
+	b.preset.Sources = append(b.preset.Sources, PresetSource{StartTime: startTime, Duration: duration})
+	return b
+}
+
+func (b *PresetBuilder) AddCodec(codec Codec) *PresetBuilder {
+	b.preset.Codecs = append(b.preset.Codecs, codec)
+	return b
+}
+
+func (b *PresetBuilder) AddOutput(fileName, formatType string) *PresetBuilder {
+	b.preset.Outputs = append(b.preset.Outputs, PresetOutput{FileName: fileName, Format: PresetFormat{Type: formatType}})
+	return b
+}
+
+// Build validates the preset and returns it as the configuration string of a task.
+func (b *PresetBuilder) Build() (string, error) {
+	if err := b.preset.Validate(); err != nil {
+		return "", errors.Wrap(err, "invalid preset")
+	}
+	configuration, err := json.Marshal(&b.preset)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to json.Marshal preset")
+	}
+	return string(configuration), nil
+}
+
+var (
+	timestampPattern     = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}(\.\d+)?$`)
+	imagePositionPattern = regexp.MustCompile(`^(\d{2}:\d{2}:\d{2}(\.\d+)?|\d+(\.\d+)?%|\d+)$`)
+	dimensionPattern     = regexp.MustCompile(`^(\d+|\d+(\.\d+)?%)$`)
+)
+
+func validateDimension(dimension string) error {
+	if len(dimension) == 0 || dimensionPattern.MatchString(dimension) {
+		return nil
+	}
+	return errors.Errorf("'%v' is neither pixels nor percentage", dimension)
+}
+
+func marshalCodec(codec Codec, fields interface{}) ([]byte, error) {
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	typeField := `{"Type":"` + codec.codecType() + `"`
+	if string(b) == "{}" {
+		return []byte(typeField + "}"), nil
+	}
+	return []byte(typeField + "," + strings.TrimPrefix(string(b), "{")), nil
+}
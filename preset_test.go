@@ -0,0 +1,109 @@
+package ams
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPresetBuilder_Build(t *testing.T) {
+	configuration, err := NewPresetBuilder().
+		AddSource("00:00:04", "00:00:10").
+		AddCodec(&H264Video{
+			KeyFrameInterval: "00:00:02",
+			H264Layers: []H264Layer{
+				{Profile: H264ProfileAuto, Bitrate: 1000, Width: "1280", Height: "720"},
+			},
+		}).
+		AddCodec(&AACAudio{Profile: AACProfileLC, Channels: 2, SamplingRate: 48000, Bitrate: 128}).
+		AddCodec(&JpgImage{
+			ImageCodec: ImageCodec{Start: "10%", Step: "10%", Range: "90%"},
+			JpgLayers:  []ImageLayer{{Width: "50%", Height: "50%", Quality: 90}},
+		}).
+		AddOutput("{Basename}_{Bitrate}{Extension}", OutputFormatMP4).
+		AddOutput("{Basename}_{Index}{Extension}", OutputFormatJpg).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual interface{}
+	if err := json.Unmarshal([]byte(configuration), &actual); err != nil {
+		t.Fatal(err)
+	}
+	var expected interface{}
+	rawExpected := `{
+  "Version": 1,
+  "Sources": [{"StartTime": "00:00:04", "Duration": "00:00:10"}],
+  "Codecs": [
+    {"Type": "H264Video", "KeyFrameInterval": "00:00:02", "H264Layers": [{"Type": "H264Layer", "Profile": "Auto", "Bitrate": 1000, "Width": "1280", "Height": "720"}]},
+    {"Type": "AACAudio", "Profile": "AACLC", "Channels": 2, "SamplingRate": 48000, "Bitrate": 128},
+    {"Type": "JpgImage", "Start": "10%", "Step": "10%", "Range": "90%", "JpgLayers": [{"Type": "JpgLayer", "Width": "50%", "Height": "50%", "Quality": 90}]}
+  ],
+  "Outputs": [
+    {"FileName": "{Basename}_{Bitrate}{Extension}", "Format": {"Type": "MP4Format"}},
+    {"FileName": "{Basename}_{Index}{Extension}", "Format": {"Type": "JpgFormat"}}
+  ]
+}`
+	if err := json.Unmarshal([]byte(rawExpected), &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected preset. expected: %v, actual: %v", expected, actual)
+	}
+}
+
+func TestPreset_Validate(t *testing.T) {
+	png := &PngImage{
+		ImageCodec: ImageCodec{Start: "{Best}"},
+		PngLayers:  []ImageLayer{{Width: "100%", Height: "100%"}},
+	}
+	cases := map[string]*PresetBuilder{
+		"missingCodecs":  NewPresetBuilder().AddOutput("out.mp4", OutputFormatMP4),
+		"missingOutputs": NewPresetBuilder().AddCodec(png),
+		"missingLayers": NewPresetBuilder().
+			AddCodec(&H264Video{}).
+			AddOutput("out.mp4", OutputFormatMP4),
+		"invalidBitrate": NewPresetBuilder().
+			AddCodec(&H264Video{H264Layers: []H264Layer{{Bitrate: 0}}}).
+			AddOutput("out.mp4", OutputFormatMP4),
+		"invalidWidth": NewPresetBuilder().
+			AddCodec(&H264Video{H264Layers: []H264Layer{{Bitrate: 1000, Width: "wide"}}}).
+			AddOutput("out.mp4", OutputFormatMP4),
+		"invalidSamplingRate": NewPresetBuilder().
+			AddCodec(&AACAudio{SamplingRate: 1}).
+			AddOutput("out.mp4", OutputFormatMP4),
+		"invalidStart": NewPresetBuilder().
+			AddCodec(&PngImage{ImageCodec: ImageCodec{Start: "best"}, PngLayers: png.PngLayers}).
+			AddOutput("out.png", OutputFormatPng),
+		"formatWithoutCodec": NewPresetBuilder().
+			AddCodec(png).
+			AddOutput("out.jpg", OutputFormatJpg),
+		"invalidSource": NewPresetBuilder().
+			AddSource("4s", "").
+			AddCodec(png).
+			AddOutput("out.png", OutputFormatPng),
+	}
+	for name, b := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := b.Build(); err == nil {
+				t.Error("accept invalid preset")
+			}
+		})
+	}
+
+	t.Run("defaultThumbnail", func(t *testing.T) {
+		if _, err := DefaultThumbnailPreset().Build(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestIsSystemPreset(t *testing.T) {
+	if !IsSystemPreset(PresetAdaptiveStreaming) {
+		t.Errorf("%v must be a system preset", PresetAdaptiveStreaming)
+	}
+	if IsSystemPreset("My Preset") {
+		t.Error("accept unknown preset")
+	}
+}
@@ -0,0 +1,30 @@
+package ams
+
+import (
+	"context"
+)
+
+const (
+	storageAccountsEndpoint = "StorageAccounts"
+)
+
+type StorageAccount struct {
+	Name      string `json:"Name"`
+	IsDefault bool   `json:"IsDefault"`
+	// BytesUsed is nil unless storage metrics are enabled on the storage account.
+	BytesUsed *int64 `json:"BytesUsed,string"`
+}
+
+func (c *Client) GetStorageAccounts(ctx context.Context) ([]StorageAccount, error) {
+	c.logger.Printf("[INFO] get storage accounts ...")
+
+	var out struct {
+		StorageAccounts []StorageAccount `json:"value"`
+	}
+	if err := c.get(ctx, storageAccountsEndpoint, &out); err != nil {
+		return nil, err
+	}
+
+	c.logger.Printf("[INFO] completed")
+	return out.StorageAccounts, nil
+}
@@ -0,0 +1,39 @@
+package ams
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_GetStorageAccounts(t *testing.T) {
+	rawStorageAccounts := `{"value":[{"Name":"samplestorage1","IsDefault":true,"BytesUsed":"1024"},{"Name":"samplestorage2","IsDefault":false,"BytesUsed":null}]}`
+	bytesUsed := int64(1024)
+	expected := []StorageAccount{
+		{Name: "samplestorage1", IsDefault: true, BytesUsed: &bytesUsed},
+		{Name: "samplestorage2", IsDefault: false},
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc("/StorageAccounts", func(w http.ResponseWriter, r *http.Request) {
+		testRequestMethod(t, r, http.MethodGet)
+		testAMSHeader(t, r, false)
+
+		fmt.Fprint(w, rawStorageAccounts)
+	})
+	s := httptest.NewServer(m)
+	defer s.Close()
+
+	client := testClient(t, s.URL)
+
+	actual, err := client.GetStorageAccounts(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected storage accounts. expected: %#v, actual: %#v", expected, actual)
+	}
+}
@@ -0,0 +1,170 @@
+package ams
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	StreamingFormatSmooth   = ""
+	StreamingFormatHLSv4    = "m3u8-aapl"
+	StreamingFormatHLSv3    = "m3u8-aapl-v3"
+	StreamingFormatDASH     = "mpd-time-csf"
+	StreamingFormatHLSCMAF  = "m3u8-cmaf"
+	StreamingFormatDASHCMAF = "mpd-time-cmaf"
+)
+
+type streamingOptions struct {
+	HostName         string
+	Filters          []string
+	DisableAudioOnly bool
+}
+
+type StreamingOption func(*streamingOptions)
+
+// SetStreamingHostName replaces the host of the locator with a custom host name of the streaming endpoint.
+func SetStreamingHostName(hostName string) StreamingOption {
+	return func(options *streamingOptions) {
+		options.HostName = hostName
+	}
+}
+
+// SetStreamingFilters applies global or asset filters to the manifest.
+func SetStreamingFilters(filters ...string) StreamingOption {
+	return func(options *streamingOptions) {
+		options.Filters = filters
+	}
+}
+
+// SetDisableAudioOnly removes the audio-only variant from HLS manifests.
+func SetDisableAudioOnly(disable bool) StreamingOption {
+	return func(options *streamingOptions) {
+		options.DisableAudioOnly = disable
+	}
+}
+
+func newStreamingOptions(opts []StreamingOption) *streamingOptions {
+	options := &streamingOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+func (l *Locator) baseStreamingURL(options *streamingOptions) (*url.URL, error) {
+	u, err := url.ParseRequestURI(l.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse url")
+	}
+	if len(options.HostName) != 0 {
+		u.Host = options.HostName
+	}
+	return u, nil
+}
+
+// ToStreamingURL builds the manifest URL of manifestName (*.ism) in format, one of StreamingFormat*.
+func (l *Locator) ToStreamingURL(manifestName, format string, opts ...StreamingOption) (*url.URL, error) {
+	options := newStreamingOptions(opts)
+	u, err := l.baseStreamingURL(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var args []string
+	if len(format) != 0 {
+		args = append(args, "format="+format)
+	}
+	if len(options.Filters) != 0 {
+		args = append(args, "filter="+strings.Join(options.Filters, ";"))
+	}
+	if options.DisableAudioOnly && strings.HasPrefix(format, "m3u8-") {
+		args = append(args, "audio-only=false")
+	}
+	manifest := "manifest"
+	if len(args) != 0 {
+		manifest += "(" + strings.Join(args, ",") + ")"
+	}
+
+	// keep the parentheses of manifest unescaped, origin servers expect them as is.
+	rawPath := path.Join(u.EscapedPath(), url.PathEscape(manifestName), manifest)
+	u.Path, err = url.PathUnescape(rawPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unescape path")
+	}
+	u.RawPath = rawPath
+	return u, nil
+}
+
+// ToProgressiveURL builds the progressive download URL of the asset file name.
+func (l *Locator) ToProgressiveURL(name string, opts ...StreamingOption) (*url.URL, error) {
+	options := newStreamingOptions(opts)
+	u, err := l.baseStreamingURL(options)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, name)
+	return u, nil
+}
+
+// StreamingURLs are the playback URLs of an asset published by an on-demand origin locator.
+// The manifest URLs are empty if the asset has no *.ism manifest.
+type StreamingURLs struct {
+	Smooth   string
+	HLSv4    string
+	HLSv3    string
+	DASH     string
+	HLSCMAF  string
+	DASHCMAF string
+	// Progressive maps the name of each MP4 asset file to its progressive download URL.
+	Progressive map[string]string
+}
+
+var progressiveExtensions = []string{".mp4", ".m4a", ".m4v"}
+
+func BuildStreamingURLs(locator *Locator, assetFiles []AssetFile, opts ...StreamingOption) (*StreamingURLs, error) {
+	if locator == nil {
+		return nil, errors.New("missing locator")
+	}
+
+	urls := &StreamingURLs{
+		Progressive: make(map[string]string),
+	}
+	for _, assetFile := range assetFiles {
+		name := assetFile.Name
+		if strings.HasSuffix(name, ".ism") && len(urls.Smooth) == 0 {
+			manifests := []struct {
+				format string
+				dst    *string
+			}{
+				{StreamingFormatSmooth, &urls.Smooth},
+				{StreamingFormatHLSv4, &urls.HLSv4},
+				{StreamingFormatHLSv3, &urls.HLSv3},
+				{StreamingFormatDASH, &urls.DASH},
+				{StreamingFormatHLSCMAF, &urls.HLSCMAF},
+				{StreamingFormatDASHCMAF, &urls.DASHCMAF},
+			}
+			for _, manifest := range manifests {
+				u, err := locator.ToStreamingURL(name, manifest.format, opts...)
+				if err != nil {
+					return nil, err
+				}
+				*manifest.dst = u.String()
+			}
+			continue
+		}
+		for _, ext := range progressiveExtensions {
+			if strings.HasSuffix(strings.ToLower(name), ext) {
+				u, err := locator.ToProgressiveURL(name, opts...)
+				if err != nil {
+					return nil, err
+				}
+				urls.Progressive[name] = u.String()
+				break
+			}
+		}
+	}
+	return urls, nil
+}
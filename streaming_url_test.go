@@ -0,0 +1,91 @@
+package ams
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLocator_ToStreamingURL(t *testing.T) {
+	locator := Locator{
+		Path: "https://fake.streaming.url/sample-locator-id/",
+	}
+	tcs := []struct {
+		Name     string
+		Format   string
+		Options  []StreamingOption
+		Expected string
+	}{
+		{
+			Name:     "smooth",
+			Format:   StreamingFormatSmooth,
+			Expected: "https://fake.streaming.url/sample-locator-id/video.ism/manifest",
+		},
+		{
+			Name:     "hlsWithFilter",
+			Format:   StreamingFormatHLSv4,
+			Options:  []StreamingOption{SetStreamingFilters("Mobile")},
+			Expected: "https://fake.streaming.url/sample-locator-id/video.ism/manifest(format=m3u8-aapl,filter=Mobile)",
+		},
+		{
+			Name:     "hlsWithoutAudioOnly",
+			Format:   StreamingFormatHLSv3,
+			Options:  []StreamingOption{SetDisableAudioOnly(true)},
+			Expected: "https://fake.streaming.url/sample-locator-id/video.ism/manifest(format=m3u8-aapl-v3,audio-only=false)",
+		},
+		{
+			Name:     "dashWithoutAudioOnly",
+			Format:   StreamingFormatDASH,
+			Options:  []StreamingOption{SetDisableAudioOnly(true)},
+			Expected: "https://fake.streaming.url/sample-locator-id/video.ism/manifest(format=mpd-time-csf)",
+		},
+		{
+			Name:     "withHostName",
+			Format:   StreamingFormatDASHCMAF,
+			Options:  []StreamingOption{SetStreamingHostName("media.example.com")},
+			Expected: "https://media.example.com/sample-locator-id/video.ism/manifest(format=mpd-time-cmaf)",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.Name, func(t *testing.T) {
+			u, err := locator.ToStreamingURL("video.ism", tc.Format, tc.Options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := u.String(); actual != tc.Expected {
+				t.Errorf("unexpected StreamingURL. expected: %v, actual: %v", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestBuildStreamingURLs(t *testing.T) {
+	locator := &Locator{
+		Path: "https://fake.streaming.url/sample-locator-id/",
+	}
+	assetFiles := []AssetFile{
+		{Name: "video.ism"},
+		{Name: "video.ismc"},
+		{Name: "video_1280x720_3400.mp4"},
+		{Name: "video_manifest.xml"},
+	}
+
+	actual, err := BuildStreamingURLs(locator, assetFiles, SetStreamingFilters("Mobile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := "https://fake.streaming.url/sample-locator-id/"
+	expected := &StreamingURLs{
+		Smooth:   base + "video.ism/manifest(filter=Mobile)",
+		HLSv4:    base + "video.ism/manifest(format=m3u8-aapl,filter=Mobile)",
+		HLSv3:    base + "video.ism/manifest(format=m3u8-aapl-v3,filter=Mobile)",
+		DASH:     base + "video.ism/manifest(format=mpd-time-csf,filter=Mobile)",
+		HLSCMAF:  base + "video.ism/manifest(format=m3u8-cmaf,filter=Mobile)",
+		DASHCMAF: base + "video.ism/manifest(format=mpd-time-cmaf,filter=Mobile)",
+		Progressive: map[string]string{
+			"video_1280x720_3400.mp4": base + "video_1280x720_3400.mp4",
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected StreamingURLs. expected: %#v, actual: %#v", expected, actual)
+	}
+}
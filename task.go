@@ -2,27 +2,57 @@ package ams
 
 import "encoding/xml"
 
+const (
+	tasksEndpoint = "Tasks"
+)
+
+const (
+	TaskNone = iota
+	TaskActive
+	TaskRunning
+	TaskCompleted
+)
+
+type ErrorDetail struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message"`
+}
+
+type TaskHistoricalEvent struct {
+	Code      string `json:"Code"`
+	Message   string `json:"Message"`
+	TimeStamp string `json:"TimeStamp"`
+}
+
+// Task is used both to submit a job and to inspect its progress.
+// The fields below TaskBody are read-only and filled by GetJobTasks.
 type Task struct {
-	Name             string `json:"Name"`
-	Configuration    string `json:"Configuration"`
-	MediaProcessorID string `json:"MediaProcessorId"`
-	TaskBody         string `json:"TaskBody"`
+	ID               string                `json:"Id,omitempty"`
+	Name             string                `json:"Name"`
+	Configuration    string                `json:"Configuration"`
+	MediaProcessorID string                `json:"MediaProcessorId"`
+	TaskBody         string                `json:"TaskBody"`
+	Options          int                   `json:"Options,omitempty"`
+	State            int                   `json:"State,omitempty"`
+	Priority         int                   `json:"Priority,omitempty"`
+	Progress         float64               `json:"Progress,omitempty"`
+	StartTime        string                `json:"StartTime,omitempty"`
+	EndTime          string                `json:"EndTime,omitempty"`
+	RunningDuration  float64               `json:"RunningDuration,omitempty"`
+	PerfMessage      string                `json:"PerfMessage,omitempty"`
+	ErrorDetails     []ErrorDetail         `json:"ErrorDetails,omitempty"`
+	HistoricalEvents []TaskHistoricalEvent `json:"HistoricalEvents,omitempty"`
 }
 
 type TaskBody struct {
-	XMLName     xml.Name `xml:"taskBody"`
-	InputAsset  AssetTag `xml:"inputAsset"`
-	OutputAsset AssetTag `xml:"outputAsset"`
+	XMLName        xml.Name   `xml:"taskBody"`
+	TaskTemplateID string     `xml:"taskTemplateId,attr,omitempty"`
+	InputAssets    []AssetTag `xml:"inputAsset"`
+	OutputAssets   []AssetTag `xml:"outputAsset"`
 }
 
 type AssetTag struct {
-	Asset string `xml:",chardata"`
-	Name  string `xml:"assetName,attr,omitempty"`
-}
-
-func newTaskBody() *TaskBody {
-	return &TaskBody{
-		InputAsset:  AssetTag{Asset: jobInputAsset},
-		OutputAsset: AssetTag{Asset: jobOutputAsset},
-	}
+	Asset           string `xml:",chardata"`
+	Name            string `xml:"assetName,attr,omitempty"`
+	CreationOptions int    `xml:"assetCreationOptions,attr,omitempty"`
 }
@@ -13,21 +13,39 @@ func TestEncodeTask(t *testing.T) {
 	}{
 		{
 			data: TaskBody{
-				InputAsset:  AssetTag{Asset: "JobInputAsset(0)"},
-				OutputAsset: AssetTag{Asset: "JobOutputAsset(0)"},
+				InputAssets:  []AssetTag{{Asset: "JobInputAsset(0)"}},
+				OutputAssets: []AssetTag{{Asset: "JobOutputAsset(0)"}},
 			},
 			expected: `<taskBody><inputAsset>JobInputAsset(0)</inputAsset><outputAsset>JobOutputAsset(0)</outputAsset></taskBody>`,
 		},
 		{
 			data: TaskBody{
-				InputAsset: AssetTag{Asset: "JobInputAsset(0)"},
-				OutputAsset: AssetTag{
-					Asset: "JobOutputAsset(0)",
-					Name:  "test",
+				InputAssets: []AssetTag{{Asset: "JobInputAsset(0)"}},
+				OutputAssets: []AssetTag{
+					{
+						Asset: "JobOutputAsset(0)",
+						Name:  "test",
+					},
 				},
 			},
 			expected: `<taskBody><inputAsset>JobInputAsset(0)</inputAsset><outputAsset assetName="test">JobOutputAsset(0)</outputAsset></taskBody>`,
 		},
+		{
+			data: TaskBody{
+				InputAssets: []AssetTag{
+					{Asset: "JobInputAsset(0)"},
+					{Asset: "JobOutputAsset(0)"},
+				},
+				OutputAssets: []AssetTag{
+					{
+						Asset:           "JobOutputAsset(1)",
+						Name:            "test",
+						CreationOptions: OptionStorageEncrypted,
+					},
+				},
+			},
+			expected: `<taskBody><inputAsset>JobInputAsset(0)</inputAsset><inputAsset>JobOutputAsset(0)</inputAsset><outputAsset assetName="test" assetCreationOptions="1">JobOutputAsset(1)</outputAsset></taskBody>`,
+		},
 	} {
 		var b bytes.Buffer
 		if err := xml.NewEncoder(&b).Encode(tc.data); err != nil {
@@ -0,0 +1,218 @@
+package ams
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultThumbnailFileName = "{Basename}_{Index}{Extension}"
+	// labeledThumbnailFileName separates the images of the codecs, which share the output, by their labels.
+	labeledThumbnailFileName = "{Basename}_{Label}_{Index}{Extension}"
+	defaultThumbnailSize     = "100%"
+)
+
+// ThumbnailConfiguration configures the images created by a thumbnail job.
+// At most one of Count, Interval, Timestamps and Start is used to choose the frames;
+// without any of them, a single image is created from the best frame.
+type ThumbnailConfiguration struct {
+	// Format is one of OutputFormatPng (default), OutputFormatJpg and OutputFormatBmp.
+	Format string
+	// Width and Height are pixels ("640") or percentages ("50%") of the video (default: "100%").
+	Width  string
+	Height string
+	// Quality is the JPEG quality (1-100).
+	Quality int
+
+	// Count creates the number of images evenly spaced over the video.
+	Count int
+	// Interval creates an image every interval.
+	Interval time.Duration
+	// Timestamps creates an image at each of the timestamps.
+	Timestamps []time.Duration
+	// Start, Step and Range are passed to Media Encoder Standard as they are.
+	Start string
+	Step  string
+	Range string
+
+	// SpriteColumns combines the images into a sprite sheet with the number of columns. It requires OutputFormatJpg.
+	SpriteColumns int
+
+	// FileName is the output file name pattern (default: "{Basename}_{Index}{Extension}").
+	// With Timestamps, each timestamp is labeled with its index in Timestamps, and the pattern must contain "{Label}"
+	// (default: "{Basename}_{Label}_{Index}{Extension}") so that their images don't overwrite each other.
+	FileName string
+}
+
+// Preset converts the configuration to a Media Encoder Standard preset.
+func (c *ThumbnailConfiguration) Preset() (*PresetBuilder, error) {
+	format := c.Format
+	if len(format) == 0 {
+		format = OutputFormatPng
+	}
+	if c.Quality != 0 && format != OutputFormatJpg {
+		return nil, errors.New("Quality requires OutputFormatJpg")
+	}
+	if c.SpriteColumns != 0 && format != OutputFormatJpg {
+		return nil, errors.New("SpriteColumns requires OutputFormatJpg")
+	}
+	if c.SpriteColumns != 0 && len(c.Timestamps) != 0 {
+		return nil, errors.New("SpriteColumns can't be used with Timestamps")
+	}
+
+	selectors := 0
+	for _, set := range []bool{c.Count != 0, c.Interval != 0, len(c.Timestamps) != 0, len(c.Start) != 0} {
+		if set {
+			selectors++
+		}
+	}
+	if selectors > 1 {
+		return nil, errors.New("only one of Count, Interval, Timestamps and Start can be set")
+	}
+
+	var positions []ImageCodec
+	switch {
+	case c.Count < 0:
+		return nil, errors.New("Count must not be negative")
+	case c.Count > 0:
+		// Range ends half a step after the last frame, so that exactly Count frames are taken
+		step := math.Round(10000/float64(c.Count)) / 100
+		frames := math.Round(100*step*(float64(c.Count)-0.5)) / 100
+		positions = append(positions, ImageCodec{Start: "0%", Step: formatPercentage(step), Range: formatPercentage(frames)})
+	case c.Interval < 0:
+		return nil, errors.New("Interval must not be negative")
+	case c.Interval > 0:
+		positions = append(positions, ImageCodec{Start: formatTimestamp(0), Step: formatTimestamp(c.Interval), Range: "100%"})
+	case len(c.Timestamps) != 0:
+		for i, timestamp := range c.Timestamps {
+			positions = append(positions, ImageCodec{Start: formatTimestamp(timestamp), Range: "1", Label: strconv.Itoa(i)})
+		}
+	case len(c.Start) != 0:
+		positions = append(positions, ImageCodec{Start: c.Start, Step: c.Step, Range: c.Range})
+	default:
+		positions = append(positions, ImageCodec{Start: "{Best}"})
+	}
+
+	layer := ImageLayer{Width: c.Width, Height: c.Height, Quality: c.Quality}
+	if len(layer.Width) == 0 {
+		layer.Width = defaultThumbnailSize
+	}
+	if len(layer.Height) == 0 {
+		layer.Height = defaultThumbnailSize
+	}
+
+	preset := NewPresetBuilder()
+	for _, position := range positions {
+		switch format {
+		case OutputFormatPng:
+			preset.AddCodec(&PngImage{ImageCodec: position, PngLayers: []ImageLayer{layer}})
+		case OutputFormatJpg:
+			preset.AddCodec(&JpgImage{ImageCodec: position, SpriteColumn: c.SpriteColumns, JpgLayers: []ImageLayer{layer}})
+		case OutputFormatBmp:
+			preset.AddCodec(&BmpImage{ImageCodec: position, BmpLayers: []ImageLayer{layer}})
+		default:
+			return nil, errors.Errorf("unsupported image format '%v'", format)
+		}
+	}
+	fileName := c.FileName
+	switch {
+	case len(fileName) != 0:
+		if len(positions) > 1 && !strings.Contains(fileName, "{Label}") {
+			return nil, errors.Errorf("FileName '%v' must contain {Label} to separate the images of Timestamps", fileName)
+		}
+	case len(positions) > 1:
+		fileName = labeledThumbnailFileName
+	default:
+		fileName = defaultThumbnailFileName
+	}
+	preset.AddOutput(fileName, format)
+	return preset, nil
+}
+
+func formatPercentage(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64) + "%"
+}
+
+// SpriteSheet returns the layout of the sprite sheet created with the configuration for a video of videoDuration.
+// Width and Height must be in pixels, and Count or Interval must be set.
+func (c *ThumbnailConfiguration) SpriteSheet(spriteURL string, videoDuration time.Duration) (*SpriteSheet, error) {
+	if c.SpriteColumns <= 0 {
+		return nil, errors.New("SpriteColumns must be greater than 0")
+	}
+	tileWidth, err := strconv.Atoi(c.Width)
+	if err != nil {
+		return nil, errors.Errorf("Width must be in pixels, but got '%v'", c.Width)
+	}
+	tileHeight, err := strconv.Atoi(c.Height)
+	if err != nil {
+		return nil, errors.Errorf("Height must be in pixels, but got '%v'", c.Height)
+	}
+	if videoDuration <= 0 {
+		return nil, errors.New("videoDuration must be greater than 0")
+	}
+
+	sheet := &SpriteSheet{
+		URL:        spriteURL,
+		Duration:   videoDuration,
+		Columns:    c.SpriteColumns,
+		TileWidth:  tileWidth,
+		TileHeight: tileHeight,
+	}
+	switch {
+	case c.Count > 0:
+		sheet.Count = c.Count
+		sheet.Interval = videoDuration / time.Duration(c.Count)
+	case c.Interval > 0:
+		sheet.Interval = c.Interval
+		sheet.Count = int((videoDuration + c.Interval - 1) / c.Interval)
+	default:
+		return nil, errors.New("Count or Interval must be set")
+	}
+	return sheet, nil
+}
+
+// SpriteSheet is the layout of a sprite sheet: Count tiles of TileWidth x TileHeight pixels,
+// Columns per row, each of which shows Interval of the video from Start.
+// Duration is the duration of the video, which ends the last tile if set.
+type SpriteSheet struct {
+	URL        string
+	Columns    int
+	TileWidth  int
+	TileHeight int
+	Count      int
+	Start      time.Duration
+	Interval   time.Duration
+	Duration   time.Duration
+}
+
+// WriteWebVTT writes a WebVTT thumbnail track, whose cues refer to the tiles by media fragments ("#xywh=x,y,w,h").
+// Players use it for the scrubbing preview.
+func (s *SpriteSheet) WriteWebVTT(w io.Writer) error {
+	if len(s.URL) == 0 {
+		return errors.New("missing URL")
+	}
+	if s.Columns <= 0 || s.TileWidth <= 0 || s.TileHeight <= 0 || s.Count <= 0 || s.Interval <= 0 {
+		return errors.New("Columns, TileWidth, TileHeight, Count and Interval must be greater than 0")
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "WEBVTT\n")
+	for i := 0; i < s.Count; i++ {
+		start := s.Start + time.Duration(i)*s.Interval
+		end := start + s.Interval
+		if s.Duration > 0 && end > s.Duration {
+			end = s.Duration
+		}
+		x := (i % s.Columns) * s.TileWidth
+		y := (i / s.Columns) * s.TileHeight
+		fmt.Fprintf(bw, "\n%s --> %s\n%s#xywh=%d,%d,%d,%d\n", formatTimestamp(start), formatTimestamp(end), s.URL, x, y, s.TileWidth, s.TileHeight)
+	}
+	return bw.Flush()
+}
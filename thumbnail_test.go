@@ -0,0 +1,184 @@
+package ams
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestThumbnailConfiguration_Preset(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   ThumbnailConfiguration
+		expected string
+	}{
+		{
+			name:   "default",
+			config: ThumbnailConfiguration{},
+			expected: `{
+  "Version": 1,
+  "Codecs": [{"Type": "PngImage", "Start": "{Best}", "PngLayers": [{"Type": "PngLayer", "Width": "100%", "Height": "100%"}]}],
+  "Outputs": [{"FileName": "{Basename}_{Index}{Extension}", "Format": {"Type": "PngFormat"}}]
+}`,
+		},
+		{
+			name:   "count",
+			config: ThumbnailConfiguration{Format: OutputFormatJpg, Width: "320", Height: "180", Quality: 80, Count: 3},
+			expected: `{
+  "Version": 1,
+  "Codecs": [{"Type": "JpgImage", "Start": "0%", "Step": "33.33%", "Range": "83.33%", "JpgLayers": [{"Type": "JpgLayer", "Width": "320", "Height": "180", "Quality": 80}]}],
+  "Outputs": [{"FileName": "{Basename}_{Index}{Extension}", "Format": {"Type": "JpgFormat"}}]
+}`,
+		},
+		{
+			name:   "timestamps",
+			config: ThumbnailConfiguration{Format: OutputFormatBmp, Timestamps: []time.Duration{time.Second, 90 * time.Second}},
+			expected: `{
+  "Version": 1,
+  "Codecs": [
+    {"Type": "BmpImage", "Start": "00:00:01.000", "Range": "1", "Label": "0", "BmpLayers": [{"Type": "BmpLayer", "Width": "100%", "Height": "100%"}]},
+    {"Type": "BmpImage", "Start": "00:01:30.000", "Range": "1", "Label": "1", "BmpLayers": [{"Type": "BmpLayer", "Width": "100%", "Height": "100%"}]}
+  ],
+  "Outputs": [{"FileName": "{Basename}_{Label}_{Index}{Extension}", "Format": {"Type": "BmpFormat"}}]
+}`,
+		},
+		{
+			name: "sprite",
+			config: ThumbnailConfiguration{
+				Format:        OutputFormatJpg,
+				Width:         "160",
+				Height:        "90",
+				Interval:      5 * time.Second,
+				SpriteColumns: 10,
+				FileName:      "{Basename}_sprite{Extension}",
+			},
+			expected: `{
+  "Version": 1,
+  "Codecs": [{"Type": "JpgImage", "Start": "00:00:00.000", "Step": "00:00:05.000", "Range": "100%", "SpriteColumn": 10, "JpgLayers": [{"Type": "JpgLayer", "Width": "160", "Height": "90"}]}],
+  "Outputs": [{"FileName": "{Basename}_sprite{Extension}", "Format": {"Type": "JpgFormat"}}]
+}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			preset, err := c.config.Preset()
+			if err != nil {
+				t.Fatal(err)
+			}
+			configuration, err := preset.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var actual, expected interface{}
+			if err := json.Unmarshal([]byte(configuration), &actual); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(c.expected), &expected); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("unexpected preset. expected: %v, actual: %v", expected, actual)
+			}
+		})
+	}
+
+	invalids := map[string]ThumbnailConfiguration{
+		"quality without jpg":      {Quality: 80},
+		"sprite without jpg":       {SpriteColumns: 4},
+		"sprite with timestamp":    {Format: OutputFormatJpg, SpriteColumns: 4, Timestamps: []time.Duration{time.Second}},
+		"count and interval":       {Count: 3, Interval: time.Second},
+		"negative count":           {Count: -1},
+		"unknown format":           {Format: "GifFormat"},
+		"timestamps without label": {Timestamps: []time.Duration{time.Second, 2 * time.Second}, FileName: "{Basename}_{Index}{Extension}"},
+	}
+	for name, config := range invalids {
+		if _, err := config.Preset(); err == nil {
+			t.Errorf("%v: expected error", name)
+		}
+	}
+}
+
+func TestThumbnailConfiguration_Preset_count(t *testing.T) {
+	parsePercentage := func(s string) float64 {
+		f, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	for _, count := range []int{1, 2, 3, 6, 7, 10, 30, 99, 100} {
+		preset, err := (&ThumbnailConfiguration{Count: count}).Preset()
+		if err != nil {
+			t.Fatal(err)
+		}
+		configuration, err := preset.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out struct {
+			Codecs []ImageCodec
+		}
+		if err := json.Unmarshal([]byte(configuration), &out); err != nil {
+			t.Fatal(err)
+		}
+		codec := out.Codecs[0]
+		start, step, end := parsePercentage(codec.Start), parsePercentage(codec.Step), parsePercentage(codec.Range)
+
+		// Media Encoder Standard takes a frame at Start and every Step within Range
+		frames := 0
+		for position := start; position <= start+end && position <= 100; position += step {
+			frames++
+		}
+		if frames != count {
+			t.Errorf("unexpected frames. Count: %d, frames: %d, codec: %#v", count, frames, codec)
+		}
+	}
+}
+
+func TestSpriteSheet_WriteWebVTT(t *testing.T) {
+	config := ThumbnailConfiguration{
+		Format:        OutputFormatJpg,
+		Width:         "160",
+		Height:        "90",
+		Interval:      5 * time.Second,
+		SpriteColumns: 2,
+	}
+	sheet, err := config.SpriteSheet("sprite.jpg", 12*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := sheet.WriteWebVTT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `WEBVTT
+
+00:00:00.000 --> 00:00:05.000
+sprite.jpg#xywh=0,0,160,90
+
+00:00:05.000 --> 00:00:10.000
+sprite.jpg#xywh=160,0,160,90
+
+00:00:10.000 --> 00:00:12.000
+sprite.jpg#xywh=0,90,160,90
+`
+	if buf.String() != expected {
+		t.Errorf("unexpected WebVTT. expected: %q, actual: %q", expected, buf.String())
+	}
+
+	cues, err := ParseWebVTT(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cues) != 3 {
+		t.Errorf("unexpected cues. expected: 3, actual: %d", len(cues))
+	}
+
+	if _, err := (&ThumbnailConfiguration{Width: "50%", Height: "90", Count: 3, SpriteColumns: 2}).SpriteSheet("sprite.jpg", time.Minute); err == nil {
+		t.Error("expected error for percentage width")
+	}
+}
@@ -0,0 +1,222 @@
+package ams
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	KeyRestrictionTypeOpen = iota
+	KeyRestrictionTypeTokenRestricted
+	KeyRestrictionTypeIPRestricted
+)
+
+const (
+	TokenTypeSWT = "SWT"
+	TokenTypeJWT = "JWT"
+)
+
+// ClaimTypeContentKeyIdentifier requires the token to carry the id of the requested content key.
+const ClaimTypeContentKeyIdentifier = "urn:microsoft:azure:mediaservices:contentkeyidentifier"
+
+const (
+	tokenRestrictionTemplateNamespace = "http://schemas.microsoft.com/Azure/MediaServices/KeyDelivery/TokenRestrictionTemplate/v1"
+	xmlSchemaInstanceNamespace        = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// ContentKeyAuthorizationPolicyRestriction is a requirement the client must meet to get the content key.
+type ContentKeyAuthorizationPolicyRestriction struct {
+	Name               string `json:"Name"`
+	KeyRestrictionType int    `json:"KeyRestrictionType"`
+	Requirements       string `json:"Requirements,omitempty"`
+}
+
+// NewOpenRestriction delivers the key to anyone.
+func NewOpenRestriction(name string) ContentKeyAuthorizationPolicyRestriction {
+	return ContentKeyAuthorizationPolicyRestriction{
+		Name:               name,
+		KeyRestrictionType: KeyRestrictionTypeOpen,
+	}
+}
+
+// NewTokenRestriction delivers the key to clients which present a token valid for template,
+// which is built by TokenRestrictionTemplateBuilder.
+func NewTokenRestriction(name, template string) ContentKeyAuthorizationPolicyRestriction {
+	return ContentKeyAuthorizationPolicyRestriction{
+		Name:               name,
+		KeyRestrictionType: KeyRestrictionTypeTokenRestricted,
+		Requirements:       template,
+	}
+}
+
+// NewIPRestriction delivers the key to clients whose address is one of addresses, which are IP addresses or CIDR ranges.
+// Requirements is the comma separated list of the addresses in their canonical form.
+func NewIPRestriction(name string, addresses ...string) (ContentKeyAuthorizationPolicyRestriction, error) {
+	if len(addresses) == 0 {
+		return ContentKeyAuthorizationPolicyRestriction{}, errors.New("missing addresses")
+	}
+	requirements := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if ip := net.ParseIP(address); ip != nil {
+			requirements = append(requirements, ip.String())
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(address)
+		if err != nil {
+			return ContentKeyAuthorizationPolicyRestriction{}, errors.Errorf("invalid address '%v'", address)
+		}
+		requirements = append(requirements, ipNet.String())
+	}
+	return ContentKeyAuthorizationPolicyRestriction{
+		Name:               name,
+		KeyRestrictionType: KeyRestrictionTypeIPRestricted,
+		Requirements:       strings.Join(requirements, ","),
+	}, nil
+}
+
+// TokenVerificationKey verifies the signature of tokens.
+type TokenVerificationKey interface {
+	verificationKey() tokenVerificationKey
+}
+
+type tokenVerificationKey struct {
+	Type     string `xml:"i:type,attr"`
+	KeyValue string `xml:"KeyValue,omitempty"`
+	RawBody  string `xml:"RawBody,omitempty"`
+}
+
+// SymmetricVerificationKey is an HMAC key shared with the token issuer. SWT tokens require it.
+type SymmetricVerificationKey struct {
+	Key []byte
+}
+
+func (k *SymmetricVerificationKey) verificationKey() tokenVerificationKey {
+	return tokenVerificationKey{Type: "SymmetricVerificationKey", KeyValue: base64.StdEncoding.EncodeToString(k.Key)}
+}
+
+// X509CertTokenVerificationKey is the DER encoded certificate of the key which signs JWT tokens.
+type X509CertTokenVerificationKey struct {
+	Certificate []byte
+}
+
+func (k *X509CertTokenVerificationKey) verificationKey() tokenVerificationKey {
+	return tokenVerificationKey{Type: "X509CertTokenVerificationKey", RawBody: base64.StdEncoding.EncodeToString(k.Certificate)}
+}
+
+type tokenClaimValue struct {
+	Nil   string `xml:"i:nil,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type tokenClaim struct {
+	ClaimType  string          `xml:"ClaimType"`
+	ClaimValue tokenClaimValue `xml:"ClaimValue"`
+}
+
+// tokenRestrictionTemplate is serialized by DataContractSerializer on the server, so the elements are in alphabetical order.
+type tokenRestrictionTemplate struct {
+	XMLName                   xml.Name               `xml:"TokenRestrictionTemplate"`
+	XMLNSI                    string                 `xml:"xmlns:i,attr"`
+	XMLNS                     string                 `xml:"xmlns,attr"`
+	AlternateVerificationKeys []tokenVerificationKey `xml:"AlternateVerificationKeys>TokenVerificationKey"`
+	Audience                  string                 `xml:"Audience"`
+	Issuer                    string                 `xml:"Issuer"`
+	PrimaryVerificationKey    *tokenVerificationKey  `xml:"PrimaryVerificationKey"`
+	RequiredClaims            []tokenClaim           `xml:"RequiredClaims>TokenClaim"`
+	TokenType                 string                 `xml:"TokenType"`
+}
+
+// TokenRestrictionTemplateBuilder builds the requirements of a token restriction.
+type TokenRestrictionTemplateBuilder struct {
+	tokenType      string
+	issuer         string
+	audience       string
+	primaryKey     TokenVerificationKey
+	alternateKeys  []TokenVerificationKey
+	requiredClaims []tokenClaim
+}
+
+func NewTokenRestrictionTemplateBuilder(tokenType, issuer, audience string) *TokenRestrictionTemplateBuilder {
+	return &TokenRestrictionTemplateBuilder{
+		tokenType: tokenType,
+		issuer:    issuer,
+		audience:  audience,
+	}
+}
+
+func (b *TokenRestrictionTemplateBuilder) SetPrimaryVerificationKey(key TokenVerificationKey) *TokenRestrictionTemplateBuilder {
+	b.primaryKey = key
+	return b
+}
+
+// AddAlternateVerificationKey adds a key which is also accepted, e.g. during key rollover.
+func (b *TokenRestrictionTemplateBuilder) AddAlternateVerificationKey(key TokenVerificationKey) *TokenRestrictionTemplateBuilder {
+	b.alternateKeys = append(b.alternateKeys, key)
+	return b
+}
+
+// AddRequiredClaim requires the token to have the claim. An empty value accepts any value.
+func (b *TokenRestrictionTemplateBuilder) AddRequiredClaim(claimType, value string) *TokenRestrictionTemplateBuilder {
+	claim := tokenClaim{ClaimType: claimType, ClaimValue: tokenClaimValue{Value: value}}
+	if len(value) == 0 {
+		claim.ClaimValue.Nil = "true"
+	}
+	b.requiredClaims = append(b.requiredClaims, claim)
+	return b
+}
+
+// Build returns the TokenRestrictionTemplate XML.
+func (b *TokenRestrictionTemplateBuilder) Build() (string, error) {
+	switch b.tokenType {
+	case TokenTypeJWT, TokenTypeSWT:
+	default:
+		return "", errors.Errorf("unknown token type '%v'", b.tokenType)
+	}
+	if len(b.issuer) == 0 {
+		return "", errors.New("missing issuer")
+	}
+	if len(b.audience) == 0 {
+		return "", errors.New("missing audience")
+	}
+	if b.primaryKey == nil {
+		return "", errors.New("missing primary verification key")
+	}
+
+	template := tokenRestrictionTemplate{
+		XMLNSI:    xmlSchemaInstanceNamespace,
+		XMLNS:     tokenRestrictionTemplateNamespace,
+		Audience:  b.audience,
+		Issuer:    b.issuer,
+		TokenType: b.tokenType,
+	}
+	for i, key := range append([]TokenVerificationKey{b.primaryKey}, b.alternateKeys...) {
+		if key == nil {
+			return "", errors.New("missing verification key")
+		}
+		if _, ok := key.(*SymmetricVerificationKey); !ok && b.tokenType == TokenTypeSWT {
+			return "", errors.New("SWT tokens require SymmetricVerificationKey")
+		}
+		verificationKey := key.verificationKey()
+		if i == 0 {
+			template.PrimaryVerificationKey = &verificationKey
+		} else {
+			template.AlternateVerificationKeys = append(template.AlternateVerificationKeys, verificationKey)
+		}
+	}
+	for _, claim := range b.requiredClaims {
+		if len(claim.ClaimType) == 0 {
+			return "", errors.New("missing claim type")
+		}
+		template.RequiredClaims = append(template.RequiredClaims, claim)
+	}
+
+	out, err := xml.Marshal(&template)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to xml.Marshal TokenRestrictionTemplate")
+	}
+	return string(out), nil
+}
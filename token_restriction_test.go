@@ -0,0 +1,60 @@
+package ams
+
+import (
+	"testing"
+)
+
+func TestTokenRestrictionTemplateBuilder_Build(t *testing.T) {
+	template, err := NewTokenRestrictionTemplateBuilder(TokenTypeJWT, "https://issuer.example.com/", "urn:test").
+		SetPrimaryVerificationKey(&SymmetricVerificationKey{Key: []byte("primary")}).
+		AddAlternateVerificationKey(&X509CertTokenVerificationKey{Certificate: []byte("cert")}).
+		AddRequiredClaim(ClaimTypeContentKeyIdentifier, "").
+		AddRequiredClaim("urn:user", "alice").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<TokenRestrictionTemplate xmlns:i="http://www.w3.org/2001/XMLSchema-instance" xmlns="http://schemas.microsoft.com/Azure/MediaServices/KeyDelivery/TokenRestrictionTemplate/v1">` +
+		`<AlternateVerificationKeys><TokenVerificationKey i:type="X509CertTokenVerificationKey"><RawBody>Y2VydA==</RawBody></TokenVerificationKey></AlternateVerificationKeys>` +
+		`<Audience>urn:test</Audience>` +
+		`<Issuer>https://issuer.example.com/</Issuer>` +
+		`<PrimaryVerificationKey i:type="SymmetricVerificationKey"><KeyValue>cHJpbWFyeQ==</KeyValue></PrimaryVerificationKey>` +
+		`<RequiredClaims>` +
+		`<TokenClaim><ClaimType>urn:microsoft:azure:mediaservices:contentkeyidentifier</ClaimType><ClaimValue i:nil="true"></ClaimValue></TokenClaim>` +
+		`<TokenClaim><ClaimType>urn:user</ClaimType><ClaimValue>alice</ClaimValue></TokenClaim>` +
+		`</RequiredClaims>` +
+		`<TokenType>JWT</TokenType>` +
+		`</TokenRestrictionTemplate>`
+	if template != expected {
+		t.Errorf("unexpected template.\nexpected: %v\nactual:   %v", expected, template)
+	}
+
+	invalids := map[string]*TokenRestrictionTemplateBuilder{
+		"unknown token type": NewTokenRestrictionTemplateBuilder("SAML", "issuer", "audience").
+			SetPrimaryVerificationKey(&SymmetricVerificationKey{Key: []byte("key")}),
+		"missing issuer": NewTokenRestrictionTemplateBuilder(TokenTypeJWT, "", "audience").
+			SetPrimaryVerificationKey(&SymmetricVerificationKey{Key: []byte("key")}),
+		"missing primary key": NewTokenRestrictionTemplateBuilder(TokenTypeJWT, "issuer", "audience"),
+		"SWT with X.509": NewTokenRestrictionTemplateBuilder(TokenTypeSWT, "issuer", "audience").
+			SetPrimaryVerificationKey(&X509CertTokenVerificationKey{Certificate: []byte("cert")}),
+	}
+	for name, builder := range invalids {
+		if _, err := builder.Build(); err == nil {
+			t.Errorf("%v: expected error", name)
+		}
+	}
+}
+
+func TestNewIPRestriction(t *testing.T) {
+	invalids := map[string][]string{
+		"missing addresses": nil,
+		"invalid address":   {"192.0.2.256"},
+		"invalid range":     {"198.51.100.0/33"},
+		"host name":         {"example.com"},
+	}
+	for name, addresses := range invalids {
+		if _, err := NewIPRestriction("office", addresses...); err == nil {
+			t.Errorf("%v: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package ams
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	EventTypeJobStateChange                     = "JobStateChange"
+	EventTypeTaskStateChange                    = "TaskStateChange"
+	EventTypeTaskProgress                       = "TaskProgress"
+	EventTypeNotificationEndPointRegistration   = "NotificationEndPointRegistration"
+	EventTypeNotificationEndPointUnregistration = "NotificationEndPointUnregistration"
+)
+
+const (
+	webhookSignatureHeader = "ms-signature"
+	webhookSignaturePrefix = "sha256="
+	maxWebhookBodySize     = 1 << 20
+)
+
+var jobStates = map[string]int{
+	"Queued":     JobQueued,
+	"Scheduled":  JobScheduled,
+	"Processing": JobProcessing,
+	"Finished":   JobFinished,
+	"Error":      JobError,
+	"Canceled":   JobCanceled,
+	"Canceling":  JobCanceling,
+}
+
+// NotificationEvent is the message AMS posts to a webhook notification endpoint.
+type NotificationEvent struct {
+	MessageVersion string            `json:"MessageVersion"`
+	ETag           string            `json:"ETag"`
+	EventType      string            `json:"EventType"`
+	TimeStamp      string            `json:"TimeStamp"`
+	Properties     map[string]string `json:"Properties"`
+}
+
+// JobStateChangeEvent has the states as Job* (e.g. JobFinished).
+type JobStateChangeEvent struct {
+	JobID                  string
+	JobName                string
+	OldState               int
+	NewState               int
+	AccountName            string
+	NotificationEndPointID string
+	TimeStamp              string
+}
+
+// TaskStateChangeEvent has the states as Job* (e.g. JobFinished), which AMS also uses for tasks in notifications.
+type TaskStateChangeEvent struct {
+	JobID                  string
+	TaskID                 string
+	TaskName               string
+	OldState               int
+	NewState               int
+	AccountName            string
+	NotificationEndPointID string
+	TimeStamp              string
+}
+
+type TaskProgressEvent struct {
+	JobID                  string
+	TaskID                 string
+	TaskName               string
+	LastComputedProgress   float64
+	AccountName            string
+	NotificationEndPointID string
+	TimeStamp              string
+}
+
+// WebhookHandler receives the notifications of a webhook notification endpoint.
+// It verifies the signature of each request with SigningKey, the plain credential of the endpoint,
+// and dispatches the events to the callbacks. Registration handshakes are acknowledged without callbacks.
+// A callback returning an error makes the handler respond 500, so AMS retries the notification.
+type WebhookHandler struct {
+	SigningKey        []byte
+	OnJobStateChange  func(event *JobStateChangeEvent) error
+	OnTaskStateChange func(event *TaskStateChangeEvent) error
+	OnTaskProgress    func(event *TaskProgressEvent) error
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if err := VerifyWebhookSignature(h.SigningKey, body, r.Header.Get(webhookSignatureHeader)); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	var event NotificationEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "failed to decode notification", http.StatusBadRequest)
+		return
+	}
+	if err := h.dispatch(&event); err != nil {
+		// a malformed notification fails on every retry, so only callback errors make AMS retry
+		if _, ok := err.(*notificationParseError); ok {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// notificationParseError is returned by dispatch when the properties of a notification are invalid.
+type notificationParseError struct {
+	err error
+}
+
+func (e *notificationParseError) Error() string {
+	return e.err.Error()
+}
+
+func parseStateChange(p map[string]string) (oldState, newState int, err error) {
+	oldState, ok := jobStates[p["OldState"]]
+	if !ok {
+		return 0, 0, &notificationParseError{err: errors.Errorf("unknown OldState '%v'", p["OldState"])}
+	}
+	newState, ok = jobStates[p["NewState"]]
+	if !ok {
+		return 0, 0, &notificationParseError{err: errors.Errorf("unknown NewState '%v'", p["NewState"])}
+	}
+	return oldState, newState, nil
+}
+
+func (h *WebhookHandler) dispatch(event *NotificationEvent) error {
+	p := event.Properties
+	switch event.EventType {
+	case EventTypeJobStateChange:
+		if h.OnJobStateChange == nil {
+			return nil
+		}
+		oldState, newState, err := parseStateChange(p)
+		if err != nil {
+			return err
+		}
+		return h.OnJobStateChange(&JobStateChangeEvent{
+			JobID:                  p["JobId"],
+			JobName:                p["JobName"],
+			OldState:               oldState,
+			NewState:               newState,
+			AccountName:            p["AccountName"],
+			NotificationEndPointID: p["NotificationEndPointId"],
+			TimeStamp:              event.TimeStamp,
+		})
+	case EventTypeTaskStateChange:
+		if h.OnTaskStateChange == nil {
+			return nil
+		}
+		oldState, newState, err := parseStateChange(p)
+		if err != nil {
+			return err
+		}
+		return h.OnTaskStateChange(&TaskStateChangeEvent{
+			JobID:                  p["JobId"],
+			TaskID:                 p["TaskId"],
+			TaskName:               p["TaskName"],
+			OldState:               oldState,
+			NewState:               newState,
+			AccountName:            p["AccountName"],
+			NotificationEndPointID: p["NotificationEndPointId"],
+			TimeStamp:              event.TimeStamp,
+		})
+	case EventTypeTaskProgress:
+		if h.OnTaskProgress == nil {
+			return nil
+		}
+		progress, err := strconv.ParseFloat(p["LastComputedProgress"], 64)
+		if err != nil {
+			return &notificationParseError{err: errors.Wrap(err, "invalid LastComputedProgress")}
+		}
+		return h.OnTaskProgress(&TaskProgressEvent{
+			JobID:                  p["JobId"],
+			TaskID:                 p["TaskId"],
+			TaskName:               p["TaskName"],
+			LastComputedProgress:   progress,
+			AccountName:            p["AccountName"],
+			NotificationEndPointID: p["NotificationEndPointId"],
+			TimeStamp:              event.TimeStamp,
+		})
+	}
+	return nil
+}
+
+// VerifyWebhookSignature checks signature ("sha256=<hex>") is the HMAC-SHA256 of body with signingKey.
+func VerifyWebhookSignature(signingKey, body []byte, signature string) error {
+	if len(signingKey) == 0 {
+		return errors.New("missing signing key")
+	}
+	if !strings.HasPrefix(signature, webhookSignaturePrefix) {
+		return errors.New("missing signature")
+	}
+	actual, err := hex.DecodeString(strings.TrimPrefix(signature, webhookSignaturePrefix))
+	if err != nil {
+		return errors.Wrap(err, "malformed signature")
+	}
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write(body)
+	if !hmac.Equal(actual, mac.Sum(nil)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package ams
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func testSignWebhook(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookHandler(t *testing.T) {
+	signingKey := []byte("sample-signing-key")
+
+	var jobEvents []JobStateChangeEvent
+	var progressEvents []TaskProgressEvent
+	h := &WebhookHandler{
+		SigningKey: signingKey,
+		OnJobStateChange: func(event *JobStateChangeEvent) error {
+			if event.JobID == "failing-job-id" {
+				return errors.New("callback failed")
+			}
+			jobEvents = append(jobEvents, *event)
+			return nil
+		},
+		OnTaskProgress: func(event *TaskProgressEvent) error {
+			progressEvents = append(progressEvents, *event)
+			return nil
+		},
+	}
+
+	cases := []struct {
+		Name       string
+		Body       string
+		Signature  func(body []byte) string
+		StatusCode int
+	}{
+		{
+			Name:       "registration",
+			Body:       `{"MessageVersion":"1.1","EventType":"NotificationEndPointRegistration","Properties":{"NotificationEndPointId":"sample-endpoint-id","State":"Registered"}}`,
+			StatusCode: http.StatusOK,
+		},
+		{
+			Name:       "jobStateChange",
+			Body:       `{"MessageVersion":"1.1","EventType":"JobStateChange","TimeStamp":"2017-08-10T02:55:10Z","Properties":{"JobId":"sample-job-id","JobName":"sample-job","OldState":"Processing","NewState":"Finished","AccountName":"sample","NotificationEndPointId":"sample-endpoint-id"}}`,
+			StatusCode: http.StatusOK,
+		},
+		{
+			Name:       "taskProgress",
+			Body:       `{"MessageVersion":"1.1","EventType":"TaskProgress","Properties":{"JobId":"sample-job-id","TaskId":"sample-task-id","LastComputedProgress":"42.5"}}`,
+			StatusCode: http.StatusOK,
+		},
+		{
+			Name:       "taskStateChangeWithoutCallback",
+			Body:       `{"MessageVersion":"1.1","EventType":"TaskStateChange","Properties":{"JobId":"sample-job-id","TaskId":"sample-task-id","OldState":"Processing","NewState":"Finished"}}`,
+			StatusCode: http.StatusOK,
+		},
+		{
+			Name:       "callbackError",
+			Body:       `{"MessageVersion":"1.1","EventType":"JobStateChange","Properties":{"JobId":"failing-job-id","OldState":"Processing","NewState":"Error"}}`,
+			StatusCode: http.StatusInternalServerError,
+		},
+		{
+			Name:       "unknownState",
+			Body:       `{"MessageVersion":"1.1","EventType":"JobStateChange","Properties":{"JobId":"sample-job-id","OldState":"Processing","NewState":"Done"}}`,
+			StatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:       "missingState",
+			Body:       `{"MessageVersion":"1.1","EventType":"JobStateChange","Properties":{"JobId":"sample-job-id","NewState":"Finished"}}`,
+			StatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:       "invalidProgress",
+			Body:       `{"MessageVersion":"1.1","EventType":"TaskProgress","Properties":{"JobId":"sample-job-id","TaskId":"sample-task-id","LastComputedProgress":"half"}}`,
+			StatusCode: http.StatusBadRequest,
+		},
+		{
+			Name:       "invalidSignature",
+			Body:       `{"MessageVersion":"1.1","EventType":"JobStateChange","Properties":{"JobId":"forged-job-id"}}`,
+			Signature:  func(body []byte) string { return testSignWebhook([]byte("wrong-key"), body) },
+			StatusCode: http.StatusUnauthorized,
+		},
+		{
+			Name:       "missingSignature",
+			Body:       `{"MessageVersion":"1.1","EventType":"JobStateChange","Properties":{"JobId":"forged-job-id"}}`,
+			Signature:  func(body []byte) string { return "" },
+			StatusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			body := []byte(tc.Body)
+			signature := testSignWebhook(signingKey, body)
+			if tc.Signature != nil {
+				signature = tc.Signature(body)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+			req.Header.Set("ms-signature", signature)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tc.StatusCode {
+				t.Errorf("unexpected status code. expected: %v, actual: %v", tc.StatusCode, w.Code)
+			}
+		})
+	}
+
+	expectedJobEvents := []JobStateChangeEvent{
+		{
+			JobID:                  "sample-job-id",
+			JobName:                "sample-job",
+			OldState:               JobProcessing,
+			NewState:               JobFinished,
+			AccountName:            "sample",
+			NotificationEndPointID: "sample-endpoint-id",
+			TimeStamp:              "2017-08-10T02:55:10Z",
+		},
+	}
+	if !reflect.DeepEqual(jobEvents, expectedJobEvents) {
+		t.Errorf("unexpected job events. expected: %#v, actual: %#v", expectedJobEvents, jobEvents)
+	}
+	expectedProgressEvents := []TaskProgressEvent{
+		{JobID: "sample-job-id", TaskID: "sample-task-id", LastComputedProgress: 42.5},
+	}
+	if !reflect.DeepEqual(progressEvents, expectedProgressEvents) {
+		t.Errorf("unexpected progress events. expected: %#v, actual: %#v", expectedProgressEvents, progressEvents)
+	}
+}